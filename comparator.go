@@ -0,0 +1,54 @@
+package version
+
+// Comparator compares versions like Compare, but memoizes the canonical
+// segments of each *Version it sees, keyed by pointer, so that a sort
+// reuses them across comparisons. Versions returned by New already cache
+// their segments; a Comparator helps with versions that don't, such as
+// the zero Version. The zero Comparator is ready to use. A Comparator is
+// not safe for concurrent use, and should be discarded once the versions
+// it has seen are no longer needed, since it keeps them alive.
+//
+//	var c version.Comparator
+//	slices.SortFunc(versions, c.Compare)
+type Comparator struct {
+	segments map[*Version][]string
+}
+
+// Compare returns -1, 0, or 1 if a is older than, the same as, or newer
+// than b, exactly as a.Compare(b) does.
+func (c *Comparator) Compare(a, b *Version) int {
+	if a.epoch != b.epoch {
+		if a.epoch > b.epoch {
+			return 1
+		}
+
+		return -1
+	}
+
+	if a.version == b.version {
+		return 0
+	}
+
+	return compareSegments(c.canonicalSegments(a), c.canonicalSegments(b), false)
+}
+
+// canonicalSegments returns the canonical segments of v, computing them
+// only the first time v is seen.
+func (c *Comparator) canonicalSegments(v *Version) []string {
+	if v.cached {
+		return v.canonical
+	}
+
+	if segments, ok := c.segments[v]; ok {
+		return segments
+	}
+
+	if c.segments == nil {
+		c.segments = make(map[*Version][]string)
+	}
+
+	segments := v.canonicalSegments()
+	c.segments[v] = segments
+
+	return segments
+}
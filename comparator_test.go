@@ -0,0 +1,81 @@
+package version
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// uncachedVersions returns n versions without construction-time cached
+// segments, as a Version built without New would be.
+func uncachedVersions(n int) []*Version {
+	versions := make([]*Version, n)
+	for i := range versions {
+		v := New2(fmt.Sprintf("%d.%d.%d", (i*7919)%13, (i*104729)%97, i%31))
+		v.cached, v.segs, v.canonical = false, nil, nil
+		versions[i] = v
+	}
+
+	return versions
+}
+
+// A Comparator orders versions exactly as Compare does.
+func Test_Comparator(t *testing.T) {
+	inputs := []string{"1.0", "1", "1.0.a", "1.10", "1.2", "1:0.1", "1.0.b.1", "0.9"}
+
+	var c Comparator
+	for _, a := range inputs {
+		for _, b := range inputs {
+			left, right := MustNew(a), MustNew(b)
+			if c.Compare(left, right) != left.Compare(right) {
+				t.Error("expected Comparator to compare", a, "and", b, "as", left.Compare(right), "but got", c.Compare(left, right))
+			}
+		}
+	}
+
+	versions := uncachedVersions(500)
+	expected := slices.Clone(versions)
+	slices.SortStableFunc(expected, CompareAscending)
+
+	var sorter Comparator
+	slices.SortStableFunc(versions, sorter.Compare)
+
+	for i := range versions {
+		if versions[i] != expected[i] {
+			t.Error("expected Comparator to sort like Compare but index", i, "was", versions[i].Version(), "instead of", expected[i].Version())
+			t.Fail()
+			return
+		}
+	}
+
+	if len(sorter.segments) != len(versions) {
+		t.Error("expected the segments of", len(versions), "versions to be memoized but got", len(sorter.segments))
+	}
+}
+
+// Sorting versions without cached segments with Compare recomputes their
+// segments on every comparison.
+func Benchmark_SortUncachedCompare(b *testing.B) {
+	versions := uncachedVersions(10000)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		sorted := slices.Clone(versions)
+		slices.SortFunc(sorted, CompareAscending)
+	}
+}
+
+// A Comparator computes the segments of each version once per sort.
+func Benchmark_SortUncachedComparator(b *testing.B) {
+	versions := uncachedVersions(10000)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		var c Comparator
+
+		sorted := slices.Clone(versions)
+		slices.SortFunc(sorted, c.Compare)
+	}
+}
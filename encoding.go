@@ -0,0 +1,69 @@
+package version
+
+import "fmt"
+
+// GobEncode implements gob.GobEncoder, encoding the version as its
+// normalized string.
+func (v *Version) GobEncode() ([]byte, error) {
+	return []byte(v.Version()), nil
+}
+
+// GobDecode implements gob.GobDecoder, parsing the encoded string with
+// New. A parse error is returned for corrupt data.
+func (v *Version) GobDecode(data []byte) error {
+	decoded, err := New(string(data))
+	if err != nil {
+		return err
+	}
+
+	*v = *decoded
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, writing the
+// normalized version string as raw bytes. Unlike gob it carries no type
+// information, which keeps cached values small.
+func (v *Version) MarshalBinary() ([]byte, error) {
+	return []byte(v.Version()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, parsing data
+// written by MarshalBinary with New. Corrupt data returns an error
+// wrapping the parse error.
+func (v *Version) UnmarshalBinary(data []byte) error {
+	decoded, err := New(string(data))
+	if err != nil {
+		return fmt.Errorf("version: invalid binary data: %w", err)
+	}
+
+	*v = *decoded
+
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface from
+// gopkg.in/yaml.v3 (and yaml.v2), encoding the version as a plain scalar.
+func (v *Version) MarshalYAML() (interface{}, error) {
+	return v.Version(), nil
+}
+
+// UnmarshalYAML implements the yaml.v2-style Unmarshaler interface, which
+// gopkg.in/yaml.v3 also honours. The scalar is decoded as a string and
+// parsed with New, returning the parse error if it is malformed.
+func (v *Version) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	decoded, err := New(s)
+	if err != nil {
+		return err
+	}
+
+	*v = *decoded
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Gob(t *testing.T) {
+	var versions []*Version
+	for _, input := range []string{"1.2.3", "1.5-3", "2.0.0+build.7", "0"} {
+		versions = append(versions, New2(input))
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(versions); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	var decoded []*Version
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if len(decoded) != len(versions) {
+		t.Error("expected", len(versions), "versions but got", len(decoded))
+		t.Fail()
+		return
+	}
+
+	for i, v := range decoded {
+		if !v.Eql(versions[i]) || v.Version() != versions[i].Version() {
+			t.Error("expected", versions[i].Version(), "but got", v.Version())
+		}
+
+		if v.Compare(versions[i]) != 0 {
+			t.Error("expected decoded", v.Version(), "to compare equal to the original")
+		}
+	}
+
+	var v Version
+	if err := v.GobDecode([]byte("1.")); err == nil {
+		t.Error("expected GobDecode to return an error for corrupt data")
+	}
+}
+
+func Test_Binary(t *testing.T) {
+	for _, input := range []string{"1.2.3", "1.5-3", "2:1.0", "2.0.0+build.7"} {
+		v := New2(input)
+
+		data, err := v.MarshalBinary()
+		if err != nil {
+			t.Error("expected err to be nil but got:", err)
+			t.Fail()
+			return
+		}
+
+		var decoded Version
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Error("expected err to be nil but got:", err)
+			t.Fail()
+			return
+		}
+
+		if !decoded.Eql(v) || decoded.Version() != v.Version() {
+			t.Error("expected", v.Version(), "but got", decoded.Version())
+		}
+	}
+
+	var v Version
+	if err := v.UnmarshalBinary([]byte("1.\x00")); !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected UnmarshalBinary to return a parse error for corrupt data but got", err)
+	}
+}
+
+// The YAML methods are exercised through the same calls a YAML library
+// makes, with the unmarshal callback decoding a scalar string.
+func Test_YAML(t *testing.T) {
+	v := New2("1.2.3-4+build")
+
+	marshalled, err := v.MarshalYAML()
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	scalar, ok := marshalled.(string)
+	if !ok || scalar != "1.2.3.pre.4+build" {
+		t.Error("expected MarshalYAML to return a plain string but got", marshalled)
+		t.Fail()
+		return
+	}
+
+	unmarshal := func(s string) func(interface{}) error {
+		return func(out interface{}) error {
+			p, ok := out.(*string)
+			if !ok {
+				return errors.New("expected a *string")
+			}
+
+			*p = s
+
+			return nil
+		}
+	}
+
+	var decoded Version
+	if err := decoded.UnmarshalYAML(unmarshal(scalar)); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if decoded.Version() != v.Version() || decoded.Compare(v) != 0 {
+		t.Error("expected", v.Version(), "but got", decoded.Version())
+	}
+
+	if err := decoded.UnmarshalYAML(unmarshal("1..2")); !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected UnmarshalYAML to return the parse error but got", err)
+	}
+}
+
+// A *Version field round-trips through a real YAML encoder and decoder.
+func Test_YAMLRoundTrip(t *testing.T) {
+	type manifest struct {
+		Name    string   `yaml:"name"`
+		Version *Version `yaml:"version"`
+	}
+
+	out, err := yaml.Marshal(manifest{Name: "widget", Version: New2("1.2.3-4+build")})
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if string(out) != "name: widget\nversion: 1.2.3.pre.4+build\n" {
+		t.Error("unexpected YAML:", string(out))
+	}
+
+	var decoded manifest
+	if err := yaml.Unmarshal(out, &decoded); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if decoded.Name != "widget" || decoded.Version == nil || decoded.Version.Version() != "1.2.3.pre.4+build" {
+		t.Error("expected widget 1.2.3.pre.4+build but got", decoded.Name, decoded.Version)
+	}
+
+	if err := yaml.Unmarshal([]byte("version: 1..2\n"), &decoded); !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected a malformed version to fail to decode but got", err)
+	}
+}
@@ -0,0 +1,57 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrMalformedVersion is the sentinel matched by errors.Is for any
+// *ErrMalformed returned by this package.
+var ErrMalformedVersion = errors.New("malformed version")
+
+// ErrNilVersion is returned by SafeCompare when either version is nil.
+var ErrNilVersion = errors.New("cannot compare a nil version")
+
+// ErrMalformed is returned when a string cannot be parsed as a version.
+// Position is the zero-based byte offset in Input of the first character
+// that could not be parsed, or len(Input) if the input ended early.
+type ErrMalformed struct {
+	Input    string
+	Position int
+}
+
+// Error implements the error interface.
+func (e *ErrMalformed) Error() string {
+	return fmt.Sprintf("malformed version number string: '%s' at position %d", e.Input, e.Position)
+}
+
+// Is reports whether target is ErrMalformedVersion.
+func (e *ErrMalformed) Is(target error) bool {
+	return target == ErrMalformedVersion
+}
+
+// malformed returns an *ErrMalformed for input, where body is the suffix
+// of input that was checked against VersionPatternAnchored after any
+// epoch or "v" prefix was removed.
+func malformed(input, body string) *ErrMalformed {
+	return &ErrMalformed{
+		Input:    input,
+		Position: len(input) - len(body) + errorPosition(body),
+	}
+}
+
+// errorPosition returns the offset of the first character in s that
+// can't begin or continue a valid version, or len(s) if every prefix of
+// s could still be completed into one. A prefix can be completed if it
+// is already valid, or becomes valid with one more alphanumeric
+// character (e.g. "1." or "1.5-").
+func errorPosition(s string) int {
+	// Completable prefixes are closed under truncation, so the first one
+	// that can't be completed can be found by binary search rather than by
+	// matching every prefix in turn, which is quadratic on long input.
+	return sort.Search(len(s), func(i int) bool {
+		prefix := s[:i+1]
+		return !anchoredPattern.MatchString(prefix) && !anchoredPattern.MatchString(prefix+"0")
+	})
+}
@@ -0,0 +1,63 @@
+package version
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_ErrMalformed(t *testing.T) {
+	_, err := New("1.")
+	if !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected New error to match ErrMalformedVersion but got", err)
+	}
+
+	var malformed *ErrMalformed
+	if !errors.As(err, &malformed) || malformed.Input != "1." {
+		t.Error("expected New error to be an *ErrMalformed for '1.' but got", err)
+	}
+
+	if err.Error() != "malformed version number string: '1.' at position 2" {
+		t.Error("unexpected error message:", err.Error())
+	}
+
+	_, err = Coerce("no numbers here")
+	if !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected Coerce error to match ErrMalformedVersion but got", err)
+	}
+
+	if !errors.As(err, &malformed) || malformed.Input != "no numbers here" {
+		t.Error("expected Coerce error to be an *ErrMalformed but got", err)
+	}
+}
+
+func Test_ErrMalformedPosition(t *testing.T) {
+	tests := map[string]int{
+		"1.":      2,
+		"1.5-":    4,
+		"1..2":    2,
+		"1.2_3":   3,
+		"  x1":    2,
+		"v1..2":   3,
+		"2:1..2":  4,
+		"1.0+b+c": 5,
+		"1:":      2,
+	}
+
+	// Long input used to take seconds as every prefix was matched in turn.
+	tests[strings.Repeat("1.", 5000)+"!"] = 10000
+
+	for input, expected := range tests {
+		_, err := New(input)
+
+		var malformed *ErrMalformed
+		if !errors.As(err, &malformed) {
+			t.Error("expected", input, "to return an *ErrMalformed but got", err)
+			continue
+		}
+
+		if malformed.Position != expected {
+			t.Error("expected position of the error in", input, "to be", expected, "but was", malformed.Position)
+		}
+	}
+}
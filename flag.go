@@ -0,0 +1,32 @@
+package version
+
+// Flag is a flag.Value holding a *Version, for use with flag.Var:
+//
+//	var min version.Flag
+//	flag.Var(&min, "min-version", "minimum supported version")
+type Flag struct {
+	V *Version
+}
+
+// Set parses s with New and stores the result. The parse error is
+// returned as-is so the flag package can report it.
+func (f *Flag) Set(s string) error {
+	v, err := New(s)
+	if err != nil {
+		return err
+	}
+
+	f.V = v
+
+	return nil
+}
+
+// String returns the version held by the flag, or an empty string if
+// it has not been set.
+func (f *Flag) String() string {
+	if f == nil || f.V == nil {
+		return ""
+	}
+
+	return f.V.Version()
+}
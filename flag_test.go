@@ -0,0 +1,33 @@
+package version
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func Test_Flag(t *testing.T) {
+	var min Flag
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&min, "min-version", "minimum version")
+
+	if err := fs.Parse([]string{"--min-version", "v1.2.3"}); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if min.V == nil || min.V.Version() != "1.2.3" {
+		t.Error("expected flag to hold version 1.2.3 but got", min.String())
+	}
+
+	if err := fs.Parse([]string{"--min-version", "1."}); err == nil {
+		t.Error("expected an error for a malformed version")
+	}
+
+	if min.String() != "1.2.3" {
+		t.Error("expected a failed Set to leave the flag unchanged but got", min.String())
+	}
+}
@@ -0,0 +1,46 @@
+package version
+
+import "testing"
+
+// FuzzNewCompare parses two strings and, when both are valid versions,
+// exercises the methods that walk their segments.
+func FuzzNewCompare(f *testing.F) {
+	f.Add("1.2.3", "1.2.3.a")
+	f.Add("1.5-3", "2.3-0-0")
+	f.Add("", "0")
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		left, err := New(a)
+		if err != nil {
+			return
+		}
+
+		right, err := New(b)
+		if err != nil {
+			return
+		}
+
+		if left.Compare(right) != -right.Compare(left) {
+			t.Errorf("Compare is not antisymmetric for %q and %q", a, b)
+		}
+
+		if left.Compare(left) != 0 {
+			t.Errorf("expected %q to compare equal to itself", a)
+		}
+
+		for _, v := range []*Version{left, right} {
+			if bumped, err := v.Bump(); err == nil && bumped == nil {
+				t.Errorf("Bump of %q returned neither a version nor an error", v.Version())
+			}
+
+			release := v.Release()
+			if release == nil {
+				t.Errorf("Release of %q returned nil", v.Version())
+			} else if release.IsPrerelease() {
+				t.Errorf("Release of %q returned prerelease %q", v.Version(), release.Version())
+			}
+
+			v.ApproximateRecommendation()
+		}
+	})
+}
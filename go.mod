@@ -1,3 +1,8 @@
 module github.com/robicode/version
 
-go 1.20
+// go 1.21 is the first release with the standard slices package, used by
+// the list and set helpers, EquivalentForms, Requirement.ToStringSorted and
+// the tests.
+go 1.21
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,159 @@
+package version
+
+import (
+	"errors"
+	"slices"
+)
+
+// ParseList parses each string with New and returns the versions sorted
+// in ascending order, with versions that compare equal (e.g. "1.0" and
+// "1") reduced to the first one given. If any string fails to parse, nil
+// and an error joining every parse failure are returned.
+func ParseList(strs []string) ([]*Version, error) {
+	var versions []*Version
+	var errs []error
+
+	for _, s := range strs {
+		v, err := New(s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return sortUnique(versions), nil
+}
+
+// ParseListLenient is like ParseList, but skips strings that fail to
+// parse instead of returning an error.
+func ParseListLenient(strs []string) []*Version {
+	var versions []*Version
+
+	for _, s := range strs {
+		if v, err := New(s); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	return sortUnique(versions)
+}
+
+// Dedup returns a new slice with versions that Compare as equal (e.g.
+// "1.0", "1" and "1.0.0") collapsed to their first occurrence. Input order
+// is otherwise preserved and nil entries are dropped.
+func Dedup(vs []*Version) []*Version {
+	var unique []*Version
+	seen := map[string]bool{}
+
+	for _, v := range vs {
+		if v == nil || seen[v.Hash()] {
+			continue
+		}
+
+		seen[v.Hash()] = true
+		unique = append(unique, v)
+	}
+
+	return unique
+}
+
+// sortUnique sorts versions in ascending order and removes versions that
+// compare equal to an earlier one.
+func sortUnique(versions []*Version) []*Version {
+	slices.SortStableFunc(versions, CompareAscending)
+
+	return slices.CompactFunc(versions, func(a, b *Version) bool {
+		return a.Compare(b) == 0
+	})
+}
+
+// SortDescending sorts vs in place from newest to oldest, so the highest
+// version is at index 0 and prereleases follow their release. The sort is
+// stable, so versions that compare equal (e.g. "1.0" and "1.0.0") keep
+// their relative order.
+func SortDescending(vs []*Version) {
+	slices.SortStableFunc(vs, func(a, b *Version) int {
+		return b.Compare(a)
+	})
+}
+
+// CommonPrefix returns the leading numeric segments shared by all of vs,
+// stopping at the first segment that differs, so 1.2.3, 1.2.9 and 1.2.0
+// share ["1", "2"]. Prerelease segments are not considered, and nil
+// versions are skipped. An empty slice is returned if vs is empty or the
+// first segments differ.
+func CommonPrefix(vs ...*Version) []string {
+	prefix := []string{}
+	first := true
+
+	for _, v := range vs {
+		if v == nil {
+			continue
+		}
+
+		numerics, _ := v.splitSegments()
+
+		if first {
+			prefix, first = numerics, false
+			continue
+		}
+
+		n := 0
+		for n < len(prefix) && n < len(numerics) && compareNumeric(prefix[n], numerics[n]) == 0 {
+			n++
+		}
+
+		prefix = prefix[:n]
+	}
+
+	return prefix
+}
+
+// FilterByMajor returns, in input order, the versions in vs whose Major
+// equals major. Nil versions are skipped.
+func FilterByMajor(vs []*Version, major int) []*Version {
+	var filtered []*Version
+
+	for _, v := range vs {
+		if v != nil && v.Major() == major {
+			filtered = append(filtered, v)
+		}
+	}
+
+	return filtered
+}
+
+// GroupByMajor buckets vs by Major, keeping the input order within each
+// bucket. Nil versions are skipped.
+func GroupByMajor(vs []*Version) map[int][]*Version {
+	groups := make(map[int][]*Version)
+
+	for _, v := range vs {
+		if v != nil {
+			groups[v.Major()] = append(groups[v.Major()], v)
+		}
+	}
+
+	return groups
+}
+
+// HighestFrom reads versions from ch until it is closed and returns the
+// highest one, or nil if no versions were received. Nil versions are
+// skipped.
+func HighestFrom(ch <-chan *Version) *Version {
+	var highest *Version
+
+	for v := range ch {
+		if v != nil && (highest == nil || v.Compare(highest) > 0) {
+			highest = v
+		}
+	}
+
+	return highest
+}
@@ -0,0 +1,187 @@
+package version
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func Test_ParseList(t *testing.T) {
+	versions, err := ParseList([]string{"1.10", "1.2", "1.0", "1", "v2.0", "1.0.a"})
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	expected := []string{"1.0.a", "1.0", "1.2", "1.10", "2.0"}
+	if len(versions) != len(expected) {
+		t.Error("expected", len(expected), "versions but got", len(versions))
+		t.Fail()
+		return
+	}
+
+	for i, v := range versions {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+
+	versions, err = ParseList([]string{"1.2", "1.", "2.0", "1.5-"})
+	if err == nil || versions != nil {
+		t.Error("expected ParseList to fail for a list containing malformed versions")
+	}
+
+	if !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected the error to match ErrMalformedVersion but got", err)
+	}
+}
+
+func Test_ParseListLenient(t *testing.T) {
+	versions := ParseListLenient([]string{"2.0", "1.", "1.2", "1.5-", "1.2.0"})
+
+	expected := []string{"1.2", "2.0"}
+	if len(versions) != len(expected) {
+		t.Error("expected", len(expected), "versions but got", len(versions))
+		t.Fail()
+		return
+	}
+
+	for i, v := range versions {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+}
+
+func Test_Dedup(t *testing.T) {
+	input := []*Version{New2("2.0"), New2("1.0"), nil, New2("1"), New2("1.0.0"), New2("2.0.0"), New2("1.a.01"), New2("1.a.1")}
+
+	result := Dedup(input)
+	if len(result) != 3 {
+		t.Error("expected 3 versions but got", len(result))
+		t.Fail()
+		return
+	}
+
+	if result[0] != input[0] || result[1] != input[1] || result[2] != input[6] {
+		t.Error("expected the first occurrences in input order but got", result[0].Version(), result[1].Version(), result[2].Version())
+	}
+
+	if len(input) != 8 || input[2] != nil {
+		t.Error("expected Dedup not to modify its input")
+	}
+}
+
+func Test_SortDescending(t *testing.T) {
+	var versions []*Version
+	for _, input := range []string{"1.0", "2.0.a", "0.9", "1.0.0", "2.0", "1.0.b.1", "1.10", "1.2"} {
+		versions = append(versions, MustNew(input))
+	}
+
+	SortDescending(versions)
+
+	expected := []string{"2.0", "2.0.a", "1.10", "1.2", "1.0", "1.0.0", "1.0.b.1", "0.9"}
+	for i, v := range versions {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+}
+
+func Test_CommonPrefix(t *testing.T) {
+	tests := []struct {
+		Versions []string
+		Expected []string
+	}{
+		{Versions: []string{"1.2.3", "1.2.9", "1.2.0"}, Expected: []string{"1", "2"}},
+		{Versions: []string{"1.2.3", "1.2.3"}, Expected: []string{"1", "2", "3"}},
+		{Versions: []string{"1.2.3", "1.2.3.a.1", "1.2"}, Expected: []string{"1", "2"}},
+		{Versions: []string{"1.2.3", "2.2.3"}, Expected: []string{}},
+		{Versions: []string{"4.5.6"}, Expected: []string{"4", "5", "6"}},
+		{Versions: []string{}, Expected: []string{}},
+	}
+
+	for _, test := range tests {
+		var versions []*Version
+		for _, input := range test.Versions {
+			versions = append(versions, MustNew(input))
+		}
+
+		prefix := CommonPrefix(versions...)
+		if prefix == nil || !slices.Equal(prefix, test.Expected) {
+			t.Error("expected CommonPrefix of", test.Versions, "to be", test.Expected, "but was", prefix)
+		}
+	}
+}
+
+func Test_FilterAndGroupByMajor(t *testing.T) {
+	var versions []*Version
+	for _, input := range []string{"1.0", "2.1", "1.5.a", "3", "2.0.0", "1.10"} {
+		versions = append(versions, MustNew(input))
+	}
+	versions = append(versions, nil)
+
+	versionStrings := func(vs []*Version) []string {
+		var result []string
+		for _, v := range vs {
+			result = append(result, v.Version())
+		}
+
+		return result
+	}
+
+	filtered := versionStrings(FilterByMajor(versions, 1))
+	if !slices.Equal(filtered, []string{"1.0", "1.5.a", "1.10"}) {
+		t.Error("expected FilterByMajor(1) to return 1.0, 1.5.a and 1.10 but got", filtered)
+	}
+
+	if FilterByMajor(versions, 4) != nil {
+		t.Error("expected FilterByMajor(4) to return nothing")
+	}
+
+	groups := GroupByMajor(versions)
+	expected := map[int][]string{
+		1: {"1.0", "1.5.a", "1.10"},
+		2: {"2.1", "2.0.0"},
+		3: {"3"},
+	}
+
+	if len(groups) != len(expected) {
+		t.Error("expected", len(expected), "groups but got", len(groups))
+	}
+
+	for major, group := range expected {
+		if !slices.Equal(versionStrings(groups[major]), group) {
+			t.Error("expected group", major, "to be", group, "but was", versionStrings(groups[major]))
+		}
+	}
+}
+
+func Test_HighestFrom(t *testing.T) {
+	ch := make(chan *Version)
+
+	go func() {
+		for _, input := range []string{"1.2", "", "1.10", "1.9", "2.0.a"} {
+			if input == "" {
+				ch <- nil
+				continue
+			}
+
+			ch <- New2(input)
+		}
+
+		close(ch)
+	}()
+
+	if highest := HighestFrom(ch); highest == nil || highest.Version() != "2.0.a" {
+		t.Error("expected HighestFrom to return 2.0.a but got", highest)
+	}
+
+	empty := make(chan *Version)
+	close(empty)
+
+	if HighestFrom(empty) != nil {
+		t.Error("expected HighestFrom to return nil for an empty channel")
+	}
+}
@@ -0,0 +1,31 @@
+package requirement
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMalformedRequirement is the sentinel matched by errors.Is for any
+// *ErrMalformed returned by this package.
+var ErrMalformedRequirement = errors.New("malformed requirement")
+
+// ErrMalformed is returned when a string cannot be parsed as a
+// requirement. Reason optionally describes what was wrong with Input.
+type ErrMalformed struct {
+	Input  string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ErrMalformed) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("unable to parse requirement: '%s'", e.Input)
+	}
+
+	return fmt.Sprintf("unable to parse requirement: '%s': %s", e.Input, e.Reason)
+}
+
+// Is reports whether target is ErrMalformedRequirement.
+func (e *ErrMalformed) Is(target error) bool {
+	return target == ErrMalformedRequirement
+}
@@ -0,0 +1,25 @@
+package requirement
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ErrMalformed(t *testing.T) {
+	for _, input := range []string{"bogus", ">=1.2 3", ">= 1.2, , < 2.0", "1.*.3"} {
+		_, err := New(input)
+		if !errors.Is(err, ErrMalformedRequirement) {
+			t.Error("expected error for", input, "to match ErrMalformedRequirement but got", err)
+		}
+
+		var malformed *ErrMalformed
+		if !errors.As(err, &malformed) {
+			t.Error("expected error for", input, "to be an *ErrMalformed but got", err)
+		}
+	}
+
+	req, _ := New(">= 1.0")
+	if _, err := req.Concat("bogus"); !errors.Is(err, ErrMalformedRequirement) {
+		t.Error("expected Concat error to match ErrMalformedRequirement but got", err)
+	}
+}
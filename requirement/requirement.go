@@ -1,14 +1,16 @@
 // A Requirement is a set of one or more version restrictions. It supports a
-// few (<tt>=, !=, >, <, >=, <=, ~></tt>) different restriction operators.
+// few (<tt>=, !=, >, <, >=, <=, ~>, ^</tt>) different restriction operators.
 //
 // See Gem::Version for a description on how versions and requirements work
 // together in RubyGems.
 package requirement
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/robicode/version"
@@ -23,6 +25,7 @@ var (
 		">=",
 		"<=",
 		"~>",
+		"^",
 	}
 
 	ops = []opFunc{
@@ -33,12 +36,28 @@ var (
 		{Op: ">=", Func: gte},
 		{Op: "<=", Func: lte},
 		{Op: "~>", Func: tildeGT},
+		{Op: "^", Func: caret},
 	}
 
-	quoted  string = strings.Join(Ops, "|")
-	pattern string = fmt.Sprintf("\\A\\s*(%s)?\\s*(%s)\\s*\\z", quoted, version.VersionPattern)
+	quoted  string = quoteOps(Ops)
+	pattern string = fmt.Sprintf("\\A\\s*(%s)?\\s*((?:[0-9]+:)?%s)\\s*\\z", quoted, version.VersionPattern)
 )
 
+// quoteOps returns ops as a regexp alternation, escaping operators such
+// as "^" that are special in a regexp.
+func quoteOps(ops []string) string {
+	quoted := make([]string, len(ops))
+	for i, op := range ops {
+		quoted[i] = regexp.QuoteMeta(op)
+	}
+
+	return strings.Join(quoted, "|")
+}
+
+// requirementPattern captures the optional operator and the version of a
+// single requirement.
+var requirementPattern = regexp.MustCompile(pattern)
+
 type operationFunc func(rs *RequirementSpecifier, v *version.Version) bool
 
 type opFunc struct {
@@ -65,6 +84,12 @@ var defaultPrereleaseRequirement RequirementSpecifier = RequirementSpecifier{
 }
 
 // main struct
+//
+// Methods that only read a *Requirement, such as IsSatisfiedBy, are safe
+// for concurrent use by multiple goroutines. Concat and UnmarshalJSON
+// modify the receiver and must not be called concurrently with other
+// methods. Methods returning a new *Requirement, such as Intersect, leave
+// the receiver untouched.
 type Requirement struct {
 	requirements []*RequirementSpecifier
 }
@@ -77,21 +102,288 @@ func DefaultPrereleaseRequirement() *RequirementSpecifier {
 	return &defaultPrereleaseRequirement
 }
 
+// New creates a new *Requirement from the given requirement strings. Each
+// string may hold a single specifier (e.g. ">= 1.2") or several
+// comma-separated specifiers (e.g. ">= 1.2, < 2.0").
 func New(requirements ...string) (*Requirement, error) {
 	var reqs []*RequirementSpecifier
 	var ret Requirement
 
 	for _, value := range requirements {
-		req, err := ret.parse(value)
+		specs, err := ret.parseAll(value)
 		if err != nil {
 			return nil, err
 		}
 
-		reqs = append(reqs, req)
+		reqs = append(reqs, specs...)
+	}
+
+	return &Requirement{
+		requirements: reqs,
+	}, nil
+}
+
+// NewSpecifier returns a *RequirementSpecifier for the operator op and the
+// version v, without formatting v back into a string. An error is
+// returned if op is not one of Ops or v is nil.
+func NewSpecifier(op string, v *version.Version) (*RequirementSpecifier, error) {
+	if v == nil {
+		return nil, &ErrMalformed{Input: op, Reason: "missing version"}
+	}
+
+	for _, value := range Ops {
+		if op == value {
+			return &RequirementSpecifier{
+				Operator: op,
+				Version:  v,
+			}, nil
+		}
+	}
+
+	return nil, &ErrMalformed{Input: op + " " + v.Version(), Reason: "invalid operator"}
+}
+
+// FromSpecifiers returns a new *Requirement made up of the given
+// specifiers, e.g. as built by NewSpecifier. Nil specifiers are skipped.
+func FromSpecifiers(specs ...*RequirementSpecifier) *Requirement {
+	var reqs []*RequirementSpecifier
+
+	for _, spec := range specs {
+		if spec != nil {
+			reqs = append(reqs, spec)
+		}
 	}
 
 	return &Requirement{
 		requirements: reqs,
+	}
+}
+
+// parseAll parses a requirement string into its specifiers. The string may
+// contain several comma-separated specifiers, and wildcard versions such
+// as "1.2.*" and hyphen ranges such as "1.2 - 2.3" expand into a pair of
+// specifiers. A blank string is the
+// default requirement ">= 0".
+func (r *Requirement) parseAll(requirement string) ([]*RequirementSpecifier, error) {
+	var specs []*RequirementSpecifier
+
+	// A blank requirement is the default, as if none had been given.
+	if strings.TrimSpace(requirement) == "" {
+		return []*RequirementSpecifier{DefaultRequirement()}, nil
+	}
+
+	pieces, err := splitRequirements(requirement)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, piece := range pieces {
+		var expanded []*RequirementSpecifier
+
+		switch {
+		case strings.Contains(piece, " - "):
+			expanded, err = parseHyphenRange(piece)
+		case strings.Contains(piece, "*"):
+			expanded, err = parseWildcard(piece)
+		default:
+			var req *RequirementSpecifier
+			req, err = r.parse(piece)
+			expanded = []*RequirementSpecifier{req}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, expanded...)
+	}
+
+	return specs, nil
+}
+
+// Approximate returns the "~>" requirement recommended for v by
+// v.ApproximateRecommendation (e.g. "~> 1.3" for 1.3.5).
+func Approximate(v *version.Version) (*Requirement, error) {
+	return New(v.ApproximateRecommendation())
+}
+
+// RangeFromPartial returns the range implied by a partial version: "1"
+// becomes ">= 1, < 2" and "1.2" becomes ">= 1.2, < 1.3". A version with
+// three or more segments, or a prerelease, is already fully specified and
+// becomes an exact "=" requirement.
+func RangeFromPartial(s string) (*Requirement, error) {
+	v, err := version.New(s)
+	if err != nil {
+		return nil, err
+	}
+
+	numerics, stringset := v.SplitSegments()
+	if len(numerics) >= 3 || len(stringset) > 0 {
+		return &Requirement{
+			requirements: []*RequirementSpecifier{{Operator: "=", Version: v}},
+		}, nil
+	}
+
+	upper, err := v.IncrementAt(len(numerics) - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Requirement{
+		requirements: []*RequirementSpecifier{
+			{Operator: ">=", Version: v},
+			{Operator: "<", Version: upper},
+		},
+	}, nil
+}
+
+// splitRequirements splits a comma-separated requirement string into its
+// individual specifiers, trimming whitespace around each one.
+func splitRequirements(requirement string) ([]string, error) {
+	if !strings.Contains(requirement, ",") {
+		return []string{requirement}, nil
+	}
+
+	pieces := strings.Split(requirement, ",")
+
+	for i, piece := range pieces {
+		pieces[i] = strings.TrimSpace(piece)
+		if pieces[i] == "" {
+			return nil, &ErrMalformed{Input: requirement, Reason: "empty requirement between commas"}
+		}
+	}
+
+	return pieces, nil
+}
+
+// wildcardPattern matches a version whose final segment is a "*" wildcard,
+// optionally preceded by the "=" operator.
+var wildcardPattern = regexp.MustCompile(`\A\s*(?:=\s*)?([0-9]+(?:\.[0-9]+)*)\.\*\s*\z`)
+
+// parseWildcard expands a wildcard version such as "1.2.*" into the
+// equivalent ">= 1.2.0" and "< 1.3.0" specifiers. The wildcard may only
+// appear as the final segment.
+func parseWildcard(requirement string) ([]*RequirementSpecifier, error) {
+	matches := wildcardPattern.FindStringSubmatch(requirement)
+	if matches == nil {
+		return nil, &ErrMalformed{Input: requirement, Reason: "invalid wildcard version"}
+	}
+
+	segments := strings.Split(matches[1], ".")
+
+	lower, err := version.New(strings.Join(append(segments, "0"), "."))
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := strconv.Atoi(segments[len(segments)-1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse wildcard requirement: '%s': %w", requirement, err)
+	}
+
+	segments[len(segments)-1] = strconv.Itoa(last + 1)
+
+	upper, err := version.New(strings.Join(append(segments, "0"), "."))
+	if err != nil {
+		return nil, err
+	}
+
+	return []*RequirementSpecifier{
+		{Operator: ">=", Version: lower},
+		{Operator: "<", Version: upper},
+	}, nil
+}
+
+// caretUpper returns the exclusive upper bound of an npm-style caret
+// range starting at lower. Changes are allowed that don't modify the
+// left-most non-zero segment, or the last given segment if all are zero:
+//
+//	^1.2.3 => >= 1.2.3, < 2.0.0
+//	^0.2.3 => >= 0.2.3, < 0.3.0
+//	^0.0.3 => >= 0.0.3, < 0.0.4
+//	^0.0   => >= 0.0, < 0.1.0
+func caretUpper(lower *version.Version) (*version.Version, error) {
+	numerics, _ := lower.SplitSegments()
+	if len(numerics) == 0 {
+		return nil, fmt.Errorf("cannot compute caret range of '%s': no numeric segments", lower.Version())
+	}
+
+	i := len(numerics) - 1
+	for j, segment := range numerics {
+		if strings.Trim(segment, "0") != "" {
+			i = j
+			break
+		}
+	}
+
+	value, err := strconv.Atoi(numerics[i])
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute caret range of '%s': %w", lower.Version(), err)
+	}
+
+	upperSegments := append([]string{}, numerics[:i]...)
+	upperSegments = append(upperSegments, strconv.Itoa(value+1))
+
+	for len(upperSegments) < 3 || len(upperSegments) < len(numerics) {
+		upperSegments = append(upperSegments, "0")
+	}
+
+	upper := strings.Join(upperSegments, ".")
+	if epoch := lower.Epoch(); epoch != 0 {
+		upper = strconv.Itoa(epoch) + ":" + upper
+	}
+
+	return version.New(upper)
+}
+
+// parseHyphenRange expands an inclusive hyphen range such as
+// "1.2.0 - 2.3.4" into ">= 1.2.0" and "<= 2.3.4". The " - " separator
+// must be surrounded by spaces so that it isn't confused with a
+// prerelease hyphen. A partial upper bound with fewer than three
+// segments covers everything it matches, so "1.2 - 2.3" becomes
+// ">= 1.2, < 2.4.0".
+func parseHyphenRange(requirement string) ([]*RequirementSpecifier, error) {
+	bounds := strings.Split(requirement, " - ")
+	if len(bounds) != 2 {
+		return nil, &ErrMalformed{Input: requirement, Reason: "a hyphen range needs exactly two versions"}
+	}
+
+	lower, err := version.New(strings.TrimSpace(bounds[0]))
+	if err != nil || strings.TrimSpace(bounds[0]) == "" {
+		return nil, &ErrMalformed{Input: requirement, Reason: "invalid lower bound"}
+	}
+
+	upper, err := version.New(strings.TrimSpace(bounds[1]))
+	if err != nil || strings.TrimSpace(bounds[1]) == "" {
+		return nil, &ErrMalformed{Input: requirement, Reason: "invalid upper bound"}
+	}
+
+	numerics, stringset := upper.SplitSegments()
+	if len(numerics) >= 3 || len(stringset) > 0 {
+		return []*RequirementSpecifier{
+			{Operator: ">=", Version: lower},
+			{Operator: "<=", Version: upper},
+		}, nil
+	}
+
+	value, err := strconv.Atoi(numerics[len(numerics)-1])
+	if err != nil {
+		return nil, &ErrMalformed{Input: requirement, Reason: "invalid upper bound"}
+	}
+
+	numerics[len(numerics)-1] = strconv.Itoa(value + 1)
+	for len(numerics) < 3 {
+		numerics = append(numerics, "0")
+	}
+
+	upper, err = version.New(strings.Join(numerics, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	return []*RequirementSpecifier{
+		{Operator: ">=", Version: lower},
+		{Operator: "<", Version: upper},
 	}, nil
 }
 
@@ -105,65 +397,419 @@ func New(requirements ...string) (*Requirement, error) {
 //	parse("> 1.0")                 # => [">", Gem::Version.new("1.0")]
 //	parse("1.0")                   # => ["=", Gem::Version.new("1.0")]
 //	parse(Gem::Version.new("1.0")) # => ["=",  Gem::Version.new("1.0")]
+//
+// The operator and version are taken from the capture groups of the
+// requirement pattern, so the space between them is optional (">=1.0" is
+// the same as ">= 1.0") and overlapping operators such as ">" and ">="
+// can't be confused.
 func (r *Requirement) parse(requirement string) (*RequirementSpecifier, error) {
-	reg := regexp.MustCompile(pattern)
-	var operator string
-
-	if !reg.MatchString(requirement) {
-		return nil, fmt.Errorf("unable to parse requirement: '%s' with regex: %s", requirement, pattern)
-	}
-
-	parts := strings.Split(requirement, " ")
-	if len(parts) != 2 {
-		return nil, errors.New("requirement should be an operator and version (e.g. '> 3.0')")
-	}
-
-	for i, value := range ops {
-		if parts[0] == ops[i].Op {
-			operator = value.Op
-			break
-		}
+	matches := requirementPattern.FindStringSubmatch(requirement)
+	if matches == nil {
+		return nil, &ErrMalformed{Input: requirement}
 	}
 
+	operator, ver := matches[1], matches[2]
 	if operator == "" {
-		return nil, fmt.Errorf("invalid operator %s", operator)
+		operator = "="
 	}
 
-	if operator == ">=" && parts[1] == "0" {
+	if operator == ">=" && ver == "0" {
 		return DefaultRequirement(), nil
-	} else if operator == ">=" && parts[1] == "0.a" {
+	} else if operator == ">=" && ver == "0.a" {
 		return DefaultPrereleaseRequirement(), nil
 	}
 
+	v, err := version.New(ver)
+	if err != nil {
+		return nil, err
+	}
+
 	return &RequirementSpecifier{
 		Operator: operator,
-		Version:  version.New2(parts[1]),
+		Version:  v,
 	}, nil
 }
 
-func (r *Requirement) Concat(requirements ...string) *Requirement {
-	for _, req := range requirements {
-		for _, registeredReq := range r.requirements {
-			splitReq, err := r.parse(req)
-			if err != nil {
-				return nil
+// Concat adds the given requirement strings to r, skipping any specifier
+// whose operator and version are already present. If any requirement
+// fails to parse, an error is returned and r is left unchanged.
+func (r *Requirement) Concat(requirements ...string) (*Requirement, error) {
+	var parsed []*RequirementSpecifier
+
+	for _, value := range requirements {
+		specs, err := r.parseAll(value)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, specs...)
+	}
+
+	for _, req := range parsed {
+		r.requirements = appendUnique(r.requirements, req)
+	}
+
+	return r, nil
+}
+
+// Operators returns the operator of each specifier, in the same order as
+// Requirements and Versions.
+func (r *Requirement) Operators() []string {
+	operators := make([]string, len(r.requirements))
+
+	for i, req := range r.requirements {
+		operators[i] = req.Operator
+	}
+
+	return operators
+}
+
+// Versions returns the version of each specifier, in the same order as
+// Requirements and Operators.
+func (r *Requirement) Versions() []*version.Version {
+	versions := make([]*version.Version, len(r.requirements))
+
+	for i, req := range r.requirements {
+		versions[i] = req.Version
+	}
+
+	return versions
+}
+
+// Contains returns true if rs is implied by the requirements in r, i.e.
+// every version satisfying r also satisfies rs. For example, a requirement
+// containing ">= 2.0" contains ">= 1.0", but one containing ">= 1.0" does
+// not contain ">= 2.0".
+//
+// Implication is worked out for the monotonic operators (>=, >, <=, <)
+// and for exact "=" pins. For other operators (!=, ~>, ^) Contains only
+// returns true if r holds an identical specifier.
+func (r *Requirement) Contains(rs *RequirementSpecifier) bool {
+	for _, req := range r.requirements {
+		if req.implies(rs) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Simplify returns a new *Requirement with redundant bounds removed: of
+// several lower bounds (>=, >) only the strictest is kept, and likewise
+// for upper bounds (<=, <). Other specifiers (=, !=, ~>, ^) are left as they
+// are. Simplify does not detect contradictory requirements such as
+// "> 2.0, < 1.0"; they are left as-is.
+func (r *Requirement) Simplify() *Requirement {
+	var reqs []*RequirementSpecifier
+
+	for i, req := range r.requirements {
+		redundant := false
+
+		if isBound(req) {
+			for j, other := range r.requirements {
+				if i == j || !isBound(other) || !other.implies(req) {
+					continue
+				}
+
+				// Equivalent bounds imply each other, so keep the first.
+				if !req.implies(other) || j < i {
+					redundant = true
+					break
+				}
 			}
+		}
 
-			if registeredReq.Operator == splitReq.Operator || splitReq.Version.Compare(registeredReq.Version) == 0 {
-				continue
+		if !redundant {
+			reqs = append(reqs, req)
+		}
+	}
+
+	return &Requirement{
+		requirements: reqs,
+	}
+}
+
+// isBound returns true if rs is a lower or upper bound.
+func isBound(rs *RequirementSpecifier) bool {
+	switch rs.Operator {
+	case ">=", ">", "<=", "<":
+		return true
+	}
+
+	return false
+}
+
+// implies returns true if every version satisfying rs also satisfies other.
+func (rs *RequirementSpecifier) implies(other *RequirementSpecifier) bool {
+	if rs.Operator == other.Operator && rs.Version.Compare(other.Version) == 0 {
+		return true
+	}
+
+	if rs.Operator == "=" {
+		return other.IsSatisfiedBy(rs.Version)
+	}
+
+	cmp := rs.Version.Compare(other.Version)
+
+	switch other.Operator {
+	case ">=":
+		return (rs.Operator == ">=" || rs.Operator == ">") && cmp >= 0
+	case ">":
+		return (rs.Operator == ">" && cmp >= 0) || (rs.Operator == ">=" && cmp > 0)
+	case "<=":
+		return (rs.Operator == "<=" || rs.Operator == "<") && cmp <= 0
+	case "<":
+		return (rs.Operator == "<" && cmp <= 0) || (rs.Operator == "<=" && cmp < 0)
+	}
+
+	return false
+}
+
+// Bounds returns the effective interval of versions allowed by r. Of the
+// lower (>=, >) and upper (<=, <) bounds the strictest is returned,
+// together with whether it is inclusive. A "~> X.Y" specifier contributes
+// both ">= X.Y" and "< X+1", a "^" specifier its caret range, and "= X"
+// both ">= X" and "<= X". A missing bound is returned as nil.
+//
+// "!=" specifiers punch holes in the interval rather than narrowing it,
+// so they are not reflected in the bounds.
+func (r *Requirement) Bounds() (lower, upper *version.Version, lowerInclusive, upperInclusive bool) {
+	setLower := func(v *version.Version, inclusive bool) {
+		if lower == nil {
+			lower, lowerInclusive = v, inclusive
+			return
+		}
+
+		cmp := v.Compare(lower)
+		if cmp > 0 || (cmp == 0 && !inclusive) {
+			lower, lowerInclusive = v, inclusive
+		}
+	}
+
+	setUpper := func(v *version.Version, inclusive bool) {
+		if upper == nil {
+			upper, upperInclusive = v, inclusive
+			return
+		}
+
+		cmp := v.Compare(upper)
+		if cmp < 0 || (cmp == 0 && !inclusive) {
+			upper, upperInclusive = v, inclusive
+		}
+	}
+
+	for _, req := range r.requirements {
+		switch req.Operator {
+		case ">=":
+			setLower(req.Version, true)
+		case ">":
+			setLower(req.Version, false)
+		case "<=":
+			setUpper(req.Version, true)
+		case "<":
+			setUpper(req.Version, false)
+		case "=":
+			setLower(req.Version, true)
+			setUpper(req.Version, true)
+		case "~>":
+			setLower(req.Version, true)
+
+			if bumped, err := req.Version.Bump(); err == nil {
+				setUpper(bumped.Release(), false)
 			}
+		case "^":
+			setLower(req.Version, true)
 
-			r.requirements = append(r.requirements, splitReq)
+			if upper, err := caretUpper(req.Version); err == nil {
+				setUpper(upper, false)
+			}
 		}
 	}
 
-	return r
+	return lower, upper, lowerInclusive, upperInclusive
 }
 
-// HasNone returns true if this *Requirement has no requirements.
+// Satisfies returns true if every version satisfying r also satisfies
+// other, i.e. if r is at least as strict. So ">= 2.0" satisfies ">= 1.0",
+// but not the other way around.
+//
+// Each specifier of other must either be implied by one of r's (see
+// Contains), or its range must hold the range given by r's Bounds. This
+// is exact for the monotonic operators (>=, >, <=, <), "=", "~>" and "^".
+// For "!=" the excluded version must lie outside r's bounds. A
+// combination that can't be decided this way, such as r needing several
+// "!=" specifiers to rule out a version, conservatively returns false, as
+// does a prerelease r against a non-prerelease other, since other then
+// rejects the prereleases r admits.
+func (r *Requirement) Satisfies(other *Requirement) bool {
+	if r.IsPrerelease() && !other.IsPrerelease() {
+		return false
+	}
+
+	lower, upper, lowerInclusive, upperInclusive := r.Bounds()
+
+	// Without a lower bound r is still limited to the lowest version it
+	// admits, so "< 3" satisfies ">= 0".
+	if lower == nil {
+		lower, lowerInclusive = DefaultRequirement().Version, true
+		if r.IsPrerelease() {
+			lower = DefaultPrereleaseRequirement().Version
+		}
+	}
+
+	for _, spec := range other.requirements {
+		if r.Contains(spec) {
+			continue
+		}
+
+		if spec.Operator == "!=" {
+			if !outside(spec.Version, lower, upper, lowerInclusive, upperInclusive) {
+				return false
+			}
+
+			continue
+		}
+
+		otherLower, otherUpper, otherLowerInclusive, otherUpperInclusive := FromSpecifiers(spec).Bounds()
+
+		if !lowerWithin(lower, lowerInclusive, otherLower, otherLowerInclusive) ||
+			!upperWithin(upper, upperInclusive, otherUpper, otherUpperInclusive) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lowerWithin returns true if the lower bound lower is at least as strict
+// as the lower bound other. A nil bound is unbounded.
+func lowerWithin(lower *version.Version, inclusive bool, other *version.Version, otherInclusive bool) bool {
+	if other == nil {
+		return true
+	}
+
+	if lower == nil {
+		return false
+	}
+
+	cmp := lower.Compare(other)
+
+	return cmp > 0 || (cmp == 0 && (otherInclusive || !inclusive))
+}
+
+// upperWithin returns true if the upper bound upper is at least as strict
+// as the upper bound other. A nil bound is unbounded.
+func upperWithin(upper *version.Version, inclusive bool, other *version.Version, otherInclusive bool) bool {
+	if other == nil {
+		return true
+	}
+
+	if upper == nil {
+		return false
+	}
+
+	cmp := upper.Compare(other)
+
+	return cmp < 0 || (cmp == 0 && (otherInclusive || !inclusive))
+}
+
+// outside returns true if v lies outside the interval given by lower and
+// upper.
+func outside(v, lower, upper *version.Version, lowerInclusive, upperInclusive bool) bool {
+	if lower != nil {
+		if cmp := v.Compare(lower); cmp < 0 || (cmp == 0 && !lowerInclusive) {
+			return true
+		}
+	}
+
+	if upper != nil {
+		if cmp := v.Compare(upper); cmp > 0 || (cmp == 0 && !upperInclusive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Requirements returns the specifiers that make up this *Requirement.
+// The returned slice and the specifiers in it are copies, so modifying
+// them does not affect r.
+func (r *Requirement) Requirements() []*RequirementSpecifier {
+	reqs := make([]*RequirementSpecifier, len(r.requirements))
+
+	for i, req := range r.requirements {
+		spec := *req
+		reqs[i] = &spec
+	}
+
+	return reqs
+}
+
+// Intersect returns a new *Requirement that is satisfied only by versions
+// satisfying both r and other. Specifiers with the same operator and an
+// equal version are only included once.
+func (r *Requirement) Intersect(other *Requirement) *Requirement {
+	var reqs []*RequirementSpecifier
+
+	for _, req := range r.requirements {
+		reqs = appendUnique(reqs, req)
+	}
+
+	if other != nil {
+		for _, req := range other.requirements {
+			reqs = appendUnique(reqs, req)
+		}
+	}
+
+	return &Requirement{
+		requirements: reqs,
+	}
+}
+
+// Tighten parses spec and returns a new *Requirement satisfied only by
+// versions satisfying both r and spec. Unlike Concat, r is not modified.
+// The parse error is returned if spec is malformed.
+func (r *Requirement) Tighten(spec string) (*Requirement, error) {
+	other, err := New(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Intersect(other), nil
+}
+
+// appendUnique appends req to reqs unless an equivalent specifier is
+// already present.
+func appendUnique(reqs []*RequirementSpecifier, req *RequirementSpecifier) []*RequirementSpecifier {
+	for _, existing := range reqs {
+		if existing.Operator == req.Operator && existing.Version.Compare(req.Version) == 0 {
+			return reqs
+		}
+	}
+
+	return append(reqs, req)
+}
+
+// HasNone returns true if this *Requirement places no restriction on
+// release versions: it has no specifiers, or only the default ">= 0" (or
+// the prerelease default ">= 0.a"). The default is matched by value, so
+// ">= 0.0" counts as well. Prereleases are still excluded by
+// IsSatisfiedBy unless the requirement is the prerelease default.
 func (r *Requirement) HasNone() bool {
-	if len(r.requirements) == 1 {
-		return r.requirements[0].Operator == DefaultRequirement().Operator && r.requirements[0].Version == DefaultRequirement().Version
+	if len(r.requirements) == 0 {
+		return true
+	}
+
+	if len(r.requirements) != 1 {
+		return false
+	}
+
+	req := r.requirements[0]
+
+	for _, def := range []*RequirementSpecifier{DefaultRequirement(), DefaultPrereleaseRequirement()} {
+		if req.Operator == def.Operator && req.Version.Compare(def.Version) == 0 {
+			return true
+		}
 	}
 
 	return false
@@ -178,6 +824,24 @@ func (r *Requirement) Exact() bool {
 	return r.requirements[0].Operator == "="
 }
 
+// ExactVersion returns the pinned *Version and true if the requirement is
+// for only an exact version, or nil and false otherwise.
+func (r *Requirement) ExactVersion() (*version.Version, bool) {
+	if !r.Exact() {
+		return nil, false
+	}
+
+	return r.requirements[0].Version, true
+}
+
+// IsExactPrerelease returns true if the requirement is for only an exact
+// version and that version is a prerelease, e.g. "= 1.0.rc.1".
+func (r *Requirement) IsExactPrerelease() bool {
+	v, ok := r.ExactVersion()
+
+	return ok && v.IsPrerelease()
+}
+
 // AsList returns the list of requirements as a []string.
 func (r *Requirement) AsList() []string {
 	var list []string
@@ -202,8 +866,34 @@ func (r *Requirement) IsPrerelease() bool {
 }
 
 // IsSatisfiedBy returns true if the given *Version satisfies all requirements
-// of the *Requirement.
+// of the *Requirement. Following RubyGems, a prerelease version only
+// satisfies a requirement that is itself a prerelease (see IsPrerelease),
+// so ">= 1.0" is not satisfied by "1.2.0.pre" but ">= 1.0.pre" is. Use
+// IsSatisfiedByAllowingPrereleases to skip this rule.
 func (r *Requirement) IsSatisfiedBy(v *version.Version) bool {
+	if v.IsPrerelease() && !r.IsPrerelease() {
+		return false
+	}
+
+	return r.IsSatisfiedByAllowingPrereleases(v)
+}
+
+// IsSatisfiedByString parses s with version.New and reports whether the
+// result satisfies r, as IsSatisfiedBy does. The parse error is returned
+// if s is not a valid version.
+func (r *Requirement) IsSatisfiedByString(s string) (bool, error) {
+	v, err := version.New(s)
+	if err != nil {
+		return false, err
+	}
+
+	return r.IsSatisfiedBy(v), nil
+}
+
+// IsSatisfiedByAllowingPrereleases is like IsSatisfiedBy, but applies each
+// specifier directly, so prerelease versions may satisfy requirements that
+// are not themselves prereleases.
+func (r *Requirement) IsSatisfiedByAllowingPrereleases(v *version.Version) bool {
 	for _, requirement := range r.requirements {
 		if !requirement.IsSatisfiedBy(v) {
 			return false
@@ -213,6 +903,164 @@ func (r *Requirement) IsSatisfiedBy(v *version.Version) bool {
 	return true
 }
 
+// Explain returns a human-readable reason for each specifier that v does
+// not satisfy (e.g. "< 2.0 not satisfied by 2.5"), plus one if v is a
+// prerelease excluded by a requirement that is not (see IsSatisfiedBy).
+// The result is empty if v satisfies the requirement.
+func (r *Requirement) Explain(v *version.Version) []string {
+	reasons := []string{}
+
+	if v.IsPrerelease() && !r.IsPrerelease() {
+		reasons = append(reasons, fmt.Sprintf("prerelease %s excluded by non-prerelease requirement %s", v.Version(), r.ToString()))
+	}
+
+	for _, req := range r.requirements {
+		if !req.IsSatisfiedBy(v) {
+			reasons = append(reasons, fmt.Sprintf("%s not satisfied by %s", req.ToString(), v.Version()))
+		}
+	}
+
+	return reasons
+}
+
+// Excludes returns true if v is explicitly excluded by a "!=" specifier
+// in the requirement. This distinguishes a blocked version from one that
+// merely falls outside the requirement's range.
+func (r *Requirement) Excludes(v *version.Version) bool {
+	for _, req := range r.requirements {
+		if req.Operator == "!=" && !req.IsSatisfiedBy(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Satisfying returns, in input order, the versions in vs that satisfy all
+// requirements of the *Requirement. Nil entries are skipped.
+func (r *Requirement) Satisfying(vs []*version.Version) []*version.Version {
+	var satisfying []*version.Version
+
+	for _, v := range vs {
+		if v != nil && r.IsSatisfiedBy(v) {
+			satisfying = append(satisfying, v)
+		}
+	}
+
+	return satisfying
+}
+
+// SatisfiedByExactlyOne returns the only version in vs that satisfies r
+// and true, or nil and false if none or more than one do. Each entry of vs
+// counts as a separate candidate, even if it compares equal to another.
+// Nil entries are skipped.
+func (r *Requirement) SatisfiedByExactlyOne(vs []*version.Version) (*version.Version, bool) {
+	var match *version.Version
+
+	for _, v := range vs {
+		if v == nil || !r.IsSatisfiedBy(v) {
+			continue
+		}
+
+		if match != nil {
+			return nil, false
+		}
+
+		match = v
+	}
+
+	return match, match != nil
+}
+
+// Dropped returns, in input order, the versions in pool that satisfy old
+// but not new, i.e. those that changing a requirement from old to new
+// would rule out. Nil versions are skipped.
+func Dropped(old, new *Requirement, pool []*version.Version) []*version.Version {
+	var dropped []*version.Version
+
+	for _, v := range pool {
+		if v != nil && old.IsSatisfiedBy(v) && !new.IsSatisfiedBy(v) {
+			dropped = append(dropped, v)
+		}
+	}
+
+	return dropped
+}
+
+// Clamp returns the version in pool that satisfies r and is closest to v:
+// the highest satisfying version not above v, or if there is none, the
+// lowest satisfying version above it. It returns nil if no version in
+// pool satisfies r. Nil entries are skipped.
+func (r *Requirement) Clamp(v *version.Version, pool []*version.Version) *version.Version {
+	var below, above *version.Version
+
+	for _, candidate := range r.Satisfying(pool) {
+		if candidate.Compare(v) <= 0 {
+			if below == nil || candidate.Compare(below) > 0 {
+				below = candidate
+			}
+		} else if above == nil || candidate.Compare(above) < 0 {
+			above = candidate
+		}
+	}
+
+	if below != nil {
+		return below
+	}
+
+	return above
+}
+
+// SatisfyingAll returns, in input order, the versions in vs that satisfy
+// every requirement in reqs. Nil versions and nil requirements are
+// skipped.
+func SatisfyingAll(reqs []*Requirement, vs []*version.Version) []*version.Version {
+	var satisfying []*version.Version
+
+	for _, v := range vs {
+		if v == nil {
+			continue
+		}
+
+		satisfied := true
+
+		for _, req := range reqs {
+			if req != nil && !req.IsSatisfiedBy(v) {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			satisfying = append(satisfying, v)
+		}
+	}
+
+	return satisfying
+}
+
+// Latest returns the highest version in vs that satisfies all requirements
+// of the *Requirement, or nil if none do.
+func (r *Requirement) Latest(vs []*version.Version) *version.Version {
+	var latest *version.Version
+
+	for _, v := range r.Satisfying(vs) {
+		if latest == nil || v.Compare(latest) == 1 {
+			latest = v
+		}
+	}
+
+	return latest
+}
+
+// LatestStable is the same as Latest. Latest already applies the
+// RubyGems prerelease rule of IsSatisfiedBy, so ">= 1.0" never selects
+// "1.2.0.pre", but ">= 1.0.a" may. It is kept for callers that want to
+// make that explicit.
+func (r *Requirement) LatestStable(vs []*version.Version) *version.Version {
+	return r.Latest(vs)
+}
+
 func (r *Requirement) IsSpecific() bool {
 	if len(r.requirements) > 1 {
 		return true
@@ -237,6 +1085,66 @@ func (r *Requirement) ToString() string {
 	return strings.Join(_strings, ", ")
 }
 
+// ToStringSorted is like ToString, but lists the specifiers in a canonical
+// order rather than the order they were added, so requirements built from
+// the same specifiers in a different order give the same string. The
+// specifiers are sorted by operator, in the order of Ops ("=", "!=", ">",
+// "<", ">=", "<=", "~>", "^"), and then by version from lowest to highest.
+func (r *Requirement) ToStringSorted() string {
+	reqs := r.Requirements()
+
+	slices.SortStableFunc(reqs, func(a, b *RequirementSpecifier) int {
+		if a.Operator != b.Operator {
+			return slices.Index(Ops, a.Operator) - slices.Index(Ops, b.Operator)
+		}
+
+		return a.Version.Compare(b.Version)
+	})
+
+	return (&Requirement{requirements: reqs}).ToString()
+}
+
+// String implements fmt.Stringer and returns the same as ToString. A nil
+// *Requirement returns an empty string.
+func (r *Requirement) String() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.ToString()
+}
+
+// MarshalJSON implements json.Marshaler, encoding the requirement as a
+// JSON array of specifier strings (e.g. ["> 1.2", "< 2.0"]).
+func (r *Requirement) MarshalJSON() ([]byte, error) {
+	list := r.AsList()
+	if list == nil {
+		list = []string{}
+	}
+
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a JSON array of
+// specifier strings. Each element is parsed like an argument to New, and
+// any parse error is returned.
+func (r *Requirement) UnmarshalJSON(data []byte) error {
+	var list []string
+
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	req, err := New(list...)
+	if err != nil {
+		return err
+	}
+
+	r.requirements = req.requirements
+
+	return nil
+}
+
 // IsSatisfiedBy returns true if a given *Version satisfies this requirement.
 func (rs *RequirementSpecifier) IsSatisfiedBy(v *version.Version) bool {
 	for _, value := range ops {
@@ -255,6 +1163,44 @@ func (rs *RequirementSpecifier) ToString() string {
 	return fmt.Sprintf("%s %s", rs.Operator, rs.Version.Version())
 }
 
+// String implements fmt.Stringer and returns the same as ToString. A nil
+// *RequirementSpecifier returns an empty string.
+func (rs *RequirementSpecifier) String() string {
+	if rs == nil {
+		return ""
+	}
+
+	return rs.ToString()
+}
+
+// negations maps each operator to the operator satisfied by exactly the
+// versions it excludes.
+var negations = map[string]string{
+	"=":  "!=",
+	"!=": "=",
+	">":  "<=",
+	"<":  ">=",
+	">=": "<",
+	"<=": ">",
+}
+
+// Negate returns the specifier satisfied by exactly the versions rs is not
+// satisfied by, so "> 1.0" becomes "<= 1.0" and "= 1.0" becomes "!= 1.0".
+// The complement of "~> 1.2" is the two-sided "< 1.2 or >= 2", which is
+// not a single specifier, so an error is returned for "~>", and likewise
+// for "^".
+func (rs *RequirementSpecifier) Negate() (*RequirementSpecifier, error) {
+	op, ok := negations[rs.Operator]
+	if !ok {
+		return nil, fmt.Errorf("cannot negate requirement '%s': its complement is not a single specifier", rs.ToString())
+	}
+
+	return &RequirementSpecifier{
+		Operator: op,
+		Version:  rs.Version,
+	}, nil
+}
+
 // Requirement operators
 func equals(rs *RequirementSpecifier, v *version.Version) bool {
 	return rs.Version.Compare(v) == 0
@@ -280,8 +1226,26 @@ func lte(rs *RequirementSpecifier, v *version.Version) bool {
 	return rs.Version.Compare(v) == 0 || rs.Version.Compare(v) == 1
 }
 
+// tildeGT is satisfied by versions >= the requirement version but below
+// its bump. If the requirement version can't be bumped it falls back to
+// behaving like >=.
 func tildeGT(rs *RequirementSpecifier, v *version.Version) bool {
-	r, _ := rs.Version.Bump()
+	r, err := rs.Version.Bump()
+	if err != nil || r == nil {
+		return gte(rs, v)
+	}
 
 	return gte(rs, v) && r.Release().Compare(v) == 1
 }
+
+// caret is satisfied by versions >= the requirement version that don't
+// change its left-most non-zero segment, as in npm. If the upper bound
+// can't be computed it falls back to behaving like >=.
+func caret(rs *RequirementSpecifier, v *version.Version) bool {
+	upper, err := caretUpper(rs.Version)
+	if err != nil {
+		return gte(rs, v)
+	}
+
+	return gte(rs, v) && upper.Compare(v) == 1
+}
@@ -1,6 +1,10 @@
 package requirement
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/robicode/version"
@@ -275,6 +279,44 @@ func Test_TildeGT(t *testing.T) {
 		t.Fail()
 		return
 	}
+
+	req, err = New("~> 1")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	if !tildeGT(req.requirements[0], version.New2("1.5")) {
+		t.Error("expected 1.5 to satisfy ~> 1")
+	}
+
+	if tildeGT(req.requirements[0], version.New2("2.0")) {
+		t.Error("expected 2.0 not to satisfy ~> 1")
+	}
+
+	req, err = New("~> 1:2.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	if !tildeGT(req.requirements[0], version.New2("1:2.5")) {
+		t.Error("expected 1:2.5 to satisfy ~> 1:2.0")
+	}
+
+	if tildeGT(req.requirements[0], version.New2("1:3.0")) {
+		t.Error("expected 1:3.0 not to satisfy ~> 1:2.0")
+	}
+
+	// A version with no numeric segments can't be bumped, so the
+	// requirement falls back to >=.
+	degenerate := &RequirementSpecifier{Operator: "~>", Version: &version.Version{}}
+
+	if !tildeGT(degenerate, version.New2("1.5")) {
+		t.Error("expected a degenerate ~> requirement to behave like >=")
+	}
 }
 
 func Test_New(t *testing.T) {
@@ -292,6 +334,36 @@ func Test_New(t *testing.T) {
 	}
 }
 
+func Test_NewSpacing(t *testing.T) {
+	tests := map[string]string{
+		">=1.0":      ">= 1.0",
+		"=1.2.3":     "= 1.2.3",
+		"~>1.2":      "~> 1.2",
+		"<=2":        "<= 2",
+		"!=1.5":      "!= 1.5",
+		"  >   1.0 ": "> 1.0",
+		"1.2":        "= 1.2",
+	}
+
+	for input, expected := range tests {
+		req, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to parse but got", err)
+			continue
+		}
+
+		if req.ToString() != expected {
+			t.Error("expected", input, "to parse as", expected, "but got", req.ToString())
+		}
+	}
+
+	for _, input := range []string{"> =1.0", "=> 1.0", ">= ", "~ > 1.2"} {
+		if _, err := New(input); !errors.Is(err, ErrMalformedRequirement) {
+			t.Error("expected", input, "to return ErrMalformedRequirement but got", err)
+		}
+	}
+}
+
 func Test_IsSatisfiedBy(t *testing.T) {
 	req, err := New(">= 1.3.5")
 	if err != nil {
@@ -337,3 +409,1340 @@ func Test_IsSatisfiedBy(t *testing.T) {
 		return
 	}
 }
+
+func Test_Requirements(t *testing.T) {
+	req, err := New("> 1.2", "< 1.4", "!= 1.3.3")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	reqs := req.Requirements()
+	if len(reqs) != 3 {
+		t.Error("expected 3 requirements but got", len(reqs))
+		t.Fail()
+		return
+	}
+
+	expected := []string{"> 1.2", "< 1.4", "!= 1.3.3"}
+	for i, rs := range reqs {
+		if rs.ToString() != expected[i] {
+			t.Error("expected requirement", i, "to be", expected[i], "but was", rs.ToString())
+		}
+	}
+
+	reqs[0] = DefaultRequirement()
+	reqs = append(reqs[:1], reqs[2:]...)
+
+	if len(req.Requirements()) != 3 || req.Requirements()[0].ToString() != "> 1.2" {
+		t.Error("expected mutating the returned slice not to affect the requirement")
+	}
+
+	req.Requirements()[0].Operator = "<"
+	if req.Requirements()[0].ToString() != "> 1.2" {
+		t.Error("expected mutating a returned specifier not to affect the requirement but got", req.ToString())
+	}
+
+	req, _ = New(">= 0")
+	req.Requirements()[0].Operator = "="
+
+	if blank, _ := New(""); blank.ToString() != ">= 0" {
+		t.Error("expected mutating a returned default specifier not to affect the default requirement but got", blank.ToString())
+	}
+}
+
+func Test_NewCommaSeparated(t *testing.T) {
+	req, err := New(">= 1.2, < 2.0,!= 1.5")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	expected := []string{">= 1.2", "< 2.0", "!= 1.5"}
+	list := req.AsList()
+
+	if len(list) != len(expected) {
+		t.Error("expected", len(expected), "requirements but got", list)
+		t.Fail()
+		return
+	}
+
+	for i, value := range list {
+		if value != expected[i] {
+			t.Error("expected requirement", i, "to be", expected[i], "but was", value)
+		}
+	}
+
+	for input, satisfied := range map[string]bool{"1.4": true, "1.5": false, "2.0": false, "1.1": false} {
+		ver, err := version.New(input)
+		if err != nil {
+			t.Error(err)
+			t.Fail()
+			return
+		}
+
+		if req.IsSatisfiedBy(ver) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	if _, err := New(">= 1.2, , < 2.0"); err == nil {
+		t.Error("expected an error for an empty requirement between commas")
+	}
+
+	if _, err := New(">= 1.2,"); err == nil {
+		t.Error("expected an error for a trailing comma")
+	}
+}
+
+func Test_String(t *testing.T) {
+	req, err := New("> 1.2", "< 2.0")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if result := fmt.Sprintf("%s", req); result != "> 1.2, < 2.0" {
+		t.Error("expected '> 1.2, < 2.0' but got", result)
+	}
+
+	if result := fmt.Sprintf("%s", req.Requirements()[0]); result != "> 1.2" {
+		t.Error("expected '> 1.2' but got", result)
+	}
+
+	var nilReq *Requirement
+	if nilReq.String() != "" {
+		t.Error("expected a nil *Requirement to return an empty string")
+	}
+
+	var nilSpec *RequirementSpecifier
+	if nilSpec.String() != "" {
+		t.Error("expected a nil *RequirementSpecifier to return an empty string")
+	}
+}
+
+func Test_Intersect(t *testing.T) {
+	left, err := New(">= 1.2", "< 2.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	right, err := New(">= 1.2.0", "!= 1.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	both := left.Intersect(right)
+
+	if both.ToString() != ">= 1.2, < 2.0, != 1.5" {
+		t.Error("expected duplicate specifiers to be removed but got", both.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.4": true, "1.5": false, "2.1": false, "1.0": false} {
+		ver, err := version.New(input)
+		if err != nil {
+			t.Error(err)
+			t.Fail()
+			return
+		}
+
+		if both.IsSatisfiedBy(ver) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+
+		if both.IsSatisfiedBy(ver) != (left.IsSatisfiedBy(ver) && right.IsSatisfiedBy(ver)) {
+			t.Error("expected the intersection to match both inputs for", input)
+		}
+	}
+
+	lower, _ := New("> 2.0")
+	upper, _ := New("< 1.0")
+	contradiction := lower.Intersect(upper)
+
+	for _, input := range []string{"0.5", "1.5", "2.5"} {
+		if contradiction.IsSatisfiedBy(version.New2(input)) {
+			t.Error("expected nothing to satisfy", contradiction.ToString(), "but", input, "did")
+		}
+	}
+}
+
+func Test_Concat(t *testing.T) {
+	req, err := New(">= 1.2", "< 2.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	result, err := req.Concat(">= 1.2", "!= 1.5", "< 2.0.0", "> 1.2")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if result != req {
+		t.Error("expected Concat to return the receiver")
+	}
+
+	if req.ToString() != ">= 1.2, < 2.0, != 1.5, > 1.2" {
+		t.Error("unexpected requirements after Concat:", req.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.2": false, "1.3": true, "1.5": false, "2.0": false} {
+		if req.IsSatisfiedBy(version.New2(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	if _, err := req.Concat("!= 1.9", "bogus"); err == nil {
+		t.Error("expected Concat to return an error for an invalid requirement")
+	}
+
+	if len(req.Requirements()) != 4 {
+		t.Error("expected a failed Concat to leave the requirement unchanged but got", req.ToString())
+	}
+}
+
+func Test_ExactVersion(t *testing.T) {
+	req, err := New("= 1.3.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	ver, ok := req.ExactVersion()
+	if !ok || ver == nil || ver.Version() != "1.3.5" {
+		t.Error("expected ExactVersion to return 1.3.5")
+	}
+
+	for _, requirements := range [][]string{{">= 1.3"}, {"= 1.3.5", "!= 1.3.4"}} {
+		req, err = New(requirements...)
+		if err != nil {
+			t.Error(err)
+			t.Fail()
+			return
+		}
+
+		if ver, ok := req.ExactVersion(); ok || ver != nil {
+			t.Error("expected", req.ToString(), "not to have an exact version")
+		}
+	}
+}
+
+func Test_IsExactPrerelease(t *testing.T) {
+	tests := map[string]bool{
+		"= 1.3.5":         false,
+		"= 1.3.5.rc.1":    true,
+		"1.4-2":           true,
+		">= 1.3.a":        false,
+		"~> 1.3.a":        false,
+		"= 1.3.a, != 1.4": false,
+	}
+
+	for input, expected := range tests {
+		req, err := New(input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if req.IsExactPrerelease() != expected {
+			t.Error("expected IsExactPrerelease() of", input, "to be", expected)
+		}
+	}
+}
+
+func Test_ToStringSorted(t *testing.T) {
+	a, err := New("< 2.0", ">= 1.0", "!= 1.5", "!= 1.2")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	b, err := New("!= 1.2", ">= 1.0", "!= 1.5", "< 2.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	expected := "!= 1.2, != 1.5, < 2.0, >= 1.0"
+
+	if a.ToStringSorted() != expected || b.ToStringSorted() != expected {
+		t.Error("expected both requirements to sort to", expected, "but got", a.ToStringSorted(), "and", b.ToStringSorted())
+	}
+
+	if a.ToString() != "< 2.0, >= 1.0, != 1.5, != 1.2" {
+		t.Error("expected ToString to keep insertion order but got", a.ToString())
+	}
+}
+
+func Test_Wildcard(t *testing.T) {
+	req, err := New("1.2.*")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != ">= 1.2.0, < 1.3.0" {
+		t.Error("expected wildcard to expand to '>= 1.2.0, < 1.3.0' but got", req.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.2.0": true, "1.2.99": true, "1.3.0": false, "1.1.9": false} {
+		if req.IsSatisfiedBy(version.New2(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	req, err = New("= 1.*", "!= 1.5")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != ">= 1.0, < 2.0, != 1.5" {
+		t.Error("unexpected requirements:", req.ToString())
+	}
+
+	for _, input := range []string{"1.*.3", "*", "> 1.2.*", "1.2*"} {
+		if _, err := New(input); err == nil {
+			t.Error("expected", input, "to be an invalid requirement")
+		}
+	}
+}
+
+func Test_SatisfyingAndLatest(t *testing.T) {
+	req, err := New("~> 1.2")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	var candidates []*version.Version
+	for _, input := range []string{"1.5", "1.1", "2.0", "1.2", "1.9.a", "2.0.a", "1.4.9"} {
+		candidates = append(candidates, version.New2(input))
+	}
+	candidates = append(candidates, nil)
+
+	satisfying := req.Satisfying(candidates)
+	expected := []string{"1.5", "1.2", "1.4.9"}
+
+	if len(satisfying) != len(expected) {
+		t.Error("expected", len(expected), "satisfying versions but got", len(satisfying))
+		t.Fail()
+		return
+	}
+
+	for i, v := range satisfying {
+		if v.Version() != expected[i] {
+			t.Error("expected satisfying version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+
+	latest := req.Latest(candidates)
+	if latest == nil || latest.Version() != "1.5" {
+		t.Error("expected Latest to return 1.5 but got", latest)
+	}
+
+	req, _ = New("> 3.0")
+	if req.Latest(candidates) != nil {
+		t.Error("expected Latest to return nil when nothing satisfies the requirement")
+	}
+}
+
+func Test_JSON(t *testing.T) {
+	req, err := New("> 1.2", "< 2.0", "!= 1.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if string(data) != `["\u003e 1.2","\u003c 2.0","!= 1.5"]` {
+		t.Error("unexpected JSON encoding:", string(data))
+	}
+
+	var decoded Requirement
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if decoded.ToString() != req.ToString() {
+		t.Error("expected", req.ToString(), "but got", decoded.ToString())
+	}
+
+	for _, input := range []string{"1.3", "1.5", "2.0", "1.0"} {
+		ver := version.New2(input)
+		if decoded.IsSatisfiedBy(ver) != req.IsSatisfiedBy(ver) {
+			t.Error("expected decoded requirement to match the original for", input)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`["> 1.2", "bogus"]`), &decoded); err == nil {
+		t.Error("expected an error for a malformed specifier")
+	}
+}
+
+func Test_Caret(t *testing.T) {
+	tests := []struct {
+		Requirement string
+		Expected    string
+		Satisfied   []string
+		Unsatisfied []string
+	}{
+		{
+			Requirement: "^1.2.3",
+			Expected:    "^ 1.2.3",
+			Satisfied:   []string{"1.2.3", "1.9.0"},
+			Unsatisfied: []string{"1.2.2", "2.0.0"},
+		},
+		{
+			Requirement: "^0.2.3",
+			Expected:    "^ 0.2.3",
+			Satisfied:   []string{"0.2.3", "0.2.9"},
+			Unsatisfied: []string{"0.2.2", "0.3.0"},
+		},
+		{
+			Requirement: "^0.0.3",
+			Expected:    "^ 0.0.3",
+			Satisfied:   []string{"0.0.3"},
+			Unsatisfied: []string{"0.0.2", "0.0.4"},
+		},
+		{
+			Requirement: "^0.0",
+			Expected:    "^ 0.0",
+			Satisfied:   []string{"0.0.9"},
+			Unsatisfied: []string{"0.1.0"},
+		},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error("expected", test.Requirement, "to be valid but got:", err)
+			continue
+		}
+
+		if req.ToString() != test.Expected {
+			t.Error("expected", test.Requirement, "to parse as", test.Expected, "but got", req.ToString())
+		}
+
+		for _, input := range test.Satisfied {
+			if !req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "to satisfy", test.Requirement)
+			}
+		}
+
+		for _, input := range test.Unsatisfied {
+			if req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "not to satisfy", test.Requirement)
+			}
+		}
+	}
+
+	for _, input := range []string{"^", "^1.", "^^1.2", "> ^1.2"} {
+		if _, err := New(input); err == nil {
+			t.Error("expected", input, "to be invalid")
+		}
+	}
+
+	req, err := New("^1:1.2.3")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	for input, expected := range map[string]bool{"1:1.9.0": true, "1.9.0": false, "1:2.0.0": false, "2:1.2.3": false} {
+		if req.IsSatisfiedBy(version.New2(input)) != expected {
+			t.Error("expected IsSatisfiedBy(", input, ") of ^ 1:1.2.3 to be", expected)
+		}
+	}
+}
+
+func Test_CaretOp(t *testing.T) {
+	req, err := New("^1.2.3")
+	if err != nil {
+		t.Error("expected New not to return error but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.requirements[0].Operator != "^" {
+		t.Error("expected ^ to be kept as the operator but got", req.requirements[0].Operator)
+		t.Fail()
+		return
+	}
+
+	if !caret(req.requirements[0], version.New2("1.9.9")) {
+		t.Error("expected 1.9.9 to satisfy ^1.2.3")
+	}
+
+	if caret(req.requirements[0], version.New2("1.2.2")) {
+		t.Error("expected 1.2.2 not to satisfy ^1.2.3")
+	}
+
+	if caret(req.requirements[0], version.New2("2.0.0")) {
+		t.Error("expected 2.0.0 not to satisfy ^1.2.3")
+	}
+
+	combined, err := req.Concat("!= 1.5.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	if combined.ToString() != "^ 1.2.3, != 1.5.0" {
+		t.Error("expected ^ to compose with Concat but got", combined.ToString())
+	}
+
+	lower, upper, _, _ := req.Bounds()
+	if lower == nil || lower.Version() != "1.2.3" || upper == nil || upper.Version() != "2.0.0" {
+		t.Error("expected the bounds of ^1.2.3 to be 1.2.3 and 2.0.0 but got", lower, upper)
+	}
+
+	// A version with no numeric segments has no caret range, so the
+	// requirement falls back to >=.
+	degenerate := &RequirementSpecifier{Operator: "^", Version: &version.Version{}}
+
+	if !caret(degenerate, version.New2("1.5")) {
+		t.Error("expected a degenerate ^ requirement to behave like >=")
+	}
+}
+
+func Test_HyphenRange(t *testing.T) {
+	tests := []struct {
+		Requirement string
+		Expected    string
+		Satisfied   []string
+		Unsatisfied []string
+	}{
+		{
+			Requirement: "1.2.0 - 2.3.4",
+			Expected:    ">= 1.2.0, <= 2.3.4",
+			Satisfied:   []string{"1.2.0", "2.3.4"},
+			Unsatisfied: []string{"1.1.9", "2.3.5"},
+		},
+		{
+			Requirement: "1.2 - 2.3",
+			Expected:    ">= 1.2, < 2.4.0",
+			Satisfied:   []string{"1.2", "2.3.9"},
+			Unsatisfied: []string{"1.1", "2.4.0"},
+		},
+		{
+			Requirement: "1.0-1 - 2.0.0-2",
+			Expected:    ">= 1.0.pre.1, <= 2.0.0.pre.2",
+			Satisfied:   []string{"1.0-1", "1.5", "2.0.0-2"},
+			Unsatisfied: []string{"1.0-0", "2.0.0"},
+		},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error("expected", test.Requirement, "to be valid but got:", err)
+			continue
+		}
+
+		if req.ToString() != test.Expected {
+			t.Error("expected", test.Requirement, "to parse as", test.Expected, "but got", req.ToString())
+		}
+
+		for _, input := range test.Satisfied {
+			if !req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "to satisfy", test.Requirement)
+			}
+		}
+
+		for _, input := range test.Unsatisfied {
+			if req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "not to satisfy", test.Requirement)
+			}
+		}
+	}
+
+	req, err := New(">= 1.2-3")
+	if err != nil || req.ToString() != ">= 1.2.pre.3" {
+		t.Error("expected a prerelease hyphen not to be treated as a range but got", req, err)
+	}
+
+	for _, input := range []string{"1.2 - ", " - 2.0", "1.2 - 2.0 - 3.0", "1.2 - bogus"} {
+		if _, err := New(input); err == nil {
+			t.Error("expected", input, "to be invalid")
+		}
+	}
+}
+
+func Test_Contains(t *testing.T) {
+	spec := func(requirement string) *RequirementSpecifier {
+		req, err := New(requirement)
+		if err != nil {
+			t.Fatal("testing bug: invalid requirement", requirement, err)
+		}
+
+		return req.requirements[0]
+	}
+
+	tests := []struct {
+		Requirement string
+		Specifier   string
+		Expected    bool
+	}{
+		{Requirement: ">= 2.0", Specifier: ">= 1.0", Expected: true},
+		{Requirement: ">= 1.0", Specifier: ">= 2.0", Expected: false},
+		{Requirement: "> 1.0", Specifier: ">= 1.0", Expected: true},
+		{Requirement: ">= 1.0", Specifier: "> 1.0", Expected: false},
+		{Requirement: ">= 1.1", Specifier: "> 1.0", Expected: true},
+		{Requirement: "< 2.0", Specifier: "<= 2.0", Expected: true},
+		{Requirement: "<= 2.0", Specifier: "< 2.0", Expected: false},
+		{Requirement: "<= 1.9", Specifier: "< 2.0", Expected: true},
+		{Requirement: "< 1.5", Specifier: "< 2.0", Expected: true},
+		{Requirement: "< 2.0", Specifier: "< 1.5", Expected: false},
+		{Requirement: "= 1.5", Specifier: "< 2.0", Expected: true},
+		{Requirement: "= 2.5", Specifier: "< 2.0", Expected: false},
+		{Requirement: ">= 1.0, < 2.0", Specifier: "< 3.0", Expected: true},
+		{Requirement: "!= 1.5", Specifier: "!= 1.5", Expected: true},
+		{Requirement: "!= 1.5", Specifier: "!= 1.6", Expected: false},
+		{Requirement: "~> 1.5", Specifier: ">= 1.0", Expected: false},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if req.Contains(spec(test.Specifier)) != test.Expected {
+			t.Error("expected", test.Requirement, "Contains", test.Specifier, "to be", test.Expected)
+		}
+	}
+}
+
+func Test_LatestStable(t *testing.T) {
+	var candidates []*version.Version
+	for _, input := range []string{"1.0", "1.1", "1.2.0.pre", "2.0.a"} {
+		candidates = append(candidates, version.New2(input))
+	}
+
+	req, _ := New(">= 1.0")
+	if latest := req.LatestStable(candidates); latest == nil || latest.Version() != "1.1" {
+		t.Error("expected a stable requirement to skip prereleases and select 1.1 but got", latest)
+	}
+
+	req, _ = New(">= 1.0.a")
+	if latest := req.LatestStable(candidates); latest == nil || latest.Version() != "2.0.a" {
+		t.Error("expected a prerelease requirement to select 2.0.a but got", latest)
+	}
+
+	req, _ = New("> 1.1")
+	if latest := req.LatestStable(candidates); latest != nil {
+		t.Error("expected no stable version to satisfy > 1.1 but got", latest.Version())
+	}
+	for _, input := range []string{">= 1.0", ">= 1.0.a", "> 1.1"} {
+		req, _ = New(input)
+		if req.LatestStable(candidates) != req.Latest(candidates) {
+			t.Error("expected LatestStable to match Latest for", input)
+		}
+	}
+}
+
+func Test_Simplify(t *testing.T) {
+	tests := map[string]string{
+		">= 1.0, >= 1.2":                ">= 1.2",
+		">= 1.2, > 1.2":                 "> 1.2",
+		"< 2.0, <= 3.0, < 2.5":          "< 2.0",
+		">= 1.0, >= 1.0.0, != 1.5":      ">= 1.0, != 1.5",
+		">= 1.0, = 1.5, ~> 1.4, >= 0.5": ">= 1.0, = 1.5, ~> 1.4",
+		"> 2.0, < 1.0":                  "> 2.0, < 1.0",
+		">= 1.0, < 2.0, >= 1.1, <= 1.9": ">= 1.1, <= 1.9",
+		"!= 1.0, != 1.0.0, >= 0.1, < 9": "!= 1.0, != 1.0.0, >= 0.1, < 9",
+	}
+
+	for input, expected := range tests {
+		req, err := New(input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		simplified := req.Simplify()
+		if simplified.ToString() != expected {
+			t.Error("expected", input, "to simplify to", expected, "but got", simplified.ToString())
+		}
+
+		for _, v := range []string{"0.5", "1.0", "1.1", "1.2", "1.5", "1.9", "2.0", "2.5", "3.0"} {
+			if req.IsSatisfiedBy(version.New2(v)) != simplified.IsSatisfiedBy(version.New2(v)) {
+				t.Error("expected simplifying", input, "not to change whether", v, "satisfies it")
+			}
+		}
+	}
+}
+
+func Test_RangeFromPartial(t *testing.T) {
+	tests := []struct {
+		Partial     string
+		Expected    string
+		Satisfied   []string
+		Unsatisfied []string
+	}{
+		{Partial: "1", Expected: ">= 1, < 2", Satisfied: []string{"1.0", "1.9.9"}, Unsatisfied: []string{"0.9", "2.0"}},
+		{Partial: "1.2", Expected: ">= 1.2, < 1.3", Satisfied: []string{"1.2.0", "1.2.9"}, Unsatisfied: []string{"1.1", "1.3"}},
+		{Partial: "1.2.3", Expected: "= 1.2.3", Satisfied: []string{"1.2.3"}, Unsatisfied: []string{"1.2.4"}},
+		{Partial: "1.2.a", Expected: "= 1.2.a", Satisfied: []string{"1.2.a"}, Unsatisfied: []string{"1.2"}},
+		{Partial: "1:1.2", Expected: ">= 1:1.2, < 1:1.3", Satisfied: []string{"1:1.2.9"}, Unsatisfied: []string{"1.2.9", "1:1.3"}},
+	}
+
+	for _, test := range tests {
+		req, err := RangeFromPartial(test.Partial)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if req.ToString() != test.Expected {
+			t.Error("expected", test.Partial, "to produce", test.Expected, "but got", req.ToString())
+		}
+
+		for _, input := range test.Satisfied {
+			if !req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "to satisfy", req.ToString())
+			}
+		}
+
+		for _, input := range test.Unsatisfied {
+			if req.IsSatisfiedBy(version.New2(input)) {
+				t.Error("expected", input, "not to satisfy", req.ToString())
+			}
+		}
+	}
+
+	if _, err := RangeFromPartial("1."); err == nil {
+		t.Error("expected an error for a malformed partial version")
+	}
+
+	req, err := RangeFromPartial("1:1.2")
+	if err != nil {
+		t.Error("expected no error but received", err)
+		t.Fail()
+		return
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Error("expected no error but received", err)
+		t.Fail()
+		return
+	}
+
+	var decoded Requirement
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ToString() != req.ToString() {
+		t.Error("expected", string(data), "to round-trip through JSON but got", decoded.ToString(), err)
+	}
+}
+
+func Test_Excludes(t *testing.T) {
+	req, err := New("> 1.0", "!= 1.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	for input, excluded := range map[string]bool{"1.5": true, "1.5.0": true, "1.6": false, "0.5": false} {
+		if req.Excludes(version.New2(input)) != excluded {
+			t.Error("expected Excludes(", input, ") to be", excluded)
+		}
+	}
+
+	if req.IsSatisfiedBy(version.New2("0.5")) || req.Excludes(version.New2("0.5")) {
+		t.Error("expected 0.5 to be out of range rather than excluded")
+	}
+}
+
+func Test_SatisfyingAll(t *testing.T) {
+	first, _ := New(">= 1.2", "< 2.0")
+	second, _ := New("~> 1.4")
+
+	var candidates []*version.Version
+	for _, input := range []string{"1.5", "1.1", "1.4.2", "1.3", "2.1", "1.9"} {
+		candidates = append(candidates, version.New2(input))
+	}
+	candidates = append(candidates, nil)
+
+	result := SatisfyingAll([]*Requirement{first, nil, second}, candidates)
+	expected := []string{"1.5", "1.4.2", "1.9"}
+
+	if len(result) != len(expected) {
+		t.Error("expected", len(expected), "versions but got", len(result))
+		t.Fail()
+		return
+	}
+
+	for i, v := range result {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+}
+
+func Test_OperatorsAndVersions(t *testing.T) {
+	req, err := New("> 1.2", "< 1.4", "!= 1.3.3")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	operators := req.Operators()
+	versions := req.Versions()
+	expectedOperators := []string{">", "<", "!="}
+	expectedVersions := []string{"1.2", "1.4", "1.3.3"}
+
+	if len(operators) != 3 || len(versions) != 3 {
+		t.Error("expected 3 operators and versions but got", operators, len(versions))
+		t.Fail()
+		return
+	}
+
+	for i := range operators {
+		if operators[i] != expectedOperators[i] || versions[i].Version() != expectedVersions[i] {
+			t.Error("expected specifier", i, "to be", expectedOperators[i], expectedVersions[i], "but was", operators[i], versions[i].Version())
+		}
+
+		if req.Requirements()[i].Operator != operators[i] {
+			t.Error("expected Operators to match the order of Requirements")
+		}
+	}
+}
+
+func Test_Explain(t *testing.T) {
+	req, err := New(">= 1.0", "< 2.0", "!= 1.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	reasons := req.Explain(version.New2("2.5"))
+	if len(reasons) != 1 || reasons[0] != "< 2.0 not satisfied by 2.5" {
+		t.Error("unexpected reasons:", reasons)
+	}
+
+	reasons = req.Explain(version.New2("1.5"))
+	if len(reasons) != 1 || reasons[0] != "!= 1.5 not satisfied by 1.5" {
+		t.Error("unexpected reasons:", reasons)
+	}
+
+	if reasons := req.Explain(version.New2("1.2")); reasons == nil || len(reasons) != 0 {
+		t.Error("expected no reasons for a satisfying version but got", reasons)
+	}
+	reasons = req.Explain(version.New2("1.2.0.pre"))
+	if len(reasons) != 1 || reasons[0] != "prerelease 1.2.0.pre excluded by non-prerelease requirement >= 1.0, < 2.0, != 1.5" {
+		t.Error("unexpected reasons:", reasons)
+	}
+
+	req, _ = New(">= 1.0.a")
+	if reasons := req.Explain(version.New2("1.2.0.pre")); len(reasons) != 0 {
+		t.Error("expected no reasons for a prerelease requirement but got", reasons)
+	}
+}
+
+func Test_Approximate(t *testing.T) {
+	req, err := Approximate(version.New2("1.3.5"))
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != "~> 1.3" {
+		t.Error("expected '~> 1.3' but got", req.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.3": true, "1.3.5": true, "1.9": true, "1.2.9": false, "2.0": false} {
+		if req.IsSatisfiedBy(version.New2(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	req, err = Approximate(version.New2("2-1"))
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != "~> 2.0.a" || !req.IsSatisfiedBy(version.New2("2.0.b")) || req.IsSatisfiedBy(version.New2("3.0")) {
+		t.Error("unexpected prerelease approximation:", req.ToString())
+	}
+
+	req, err = Approximate(version.New2("1:1.3.5"))
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != "~> 1:1.3" || !req.IsSatisfiedBy(version.New2("1:1.3.5")) || req.IsSatisfiedBy(version.New2("1.3.5")) {
+		t.Error("unexpected epoch approximation:", req.ToString())
+	}
+}
+
+func Test_IsSatisfiedByPrerelease(t *testing.T) {
+	pre := version.New2("1.2.0.pre")
+
+	req, _ := New(">= 1.0")
+	if req.IsSatisfiedBy(pre) {
+		t.Error("expected 1.2.0.pre not to satisfy >= 1.0")
+	}
+
+	if !req.IsSatisfiedByAllowingPrereleases(pre) {
+		t.Error("expected 1.2.0.pre to satisfy >= 1.0 when prereleases are allowed")
+	}
+
+	req, _ = New(">= 1.0.pre")
+	if !req.IsSatisfiedBy(pre) {
+		t.Error("expected 1.2.0.pre to satisfy >= 1.0.pre")
+	}
+
+	req, _ = New(">= 1.0.pre", "< 2.0")
+	if !req.IsSatisfiedBy(pre) || req.IsSatisfiedBy(version.New2("2.1.pre")) {
+		t.Error("expected a prerelease specifier to allow prereleases within the range only")
+	}
+}
+
+func Test_Bounds(t *testing.T) {
+	tests := []struct {
+		Requirement    string
+		Lower          string
+		Upper          string
+		LowerInclusive bool
+		UpperInclusive bool
+	}{
+		{Requirement: "~> 1.4", Lower: "1.4", Upper: "2", LowerInclusive: true},
+		{Requirement: "~> 1.4.2", Lower: "1.4.2", Upper: "1.5", LowerInclusive: true},
+		{Requirement: "> 1.0, <= 2.0", Lower: "1.0", Upper: "2.0", UpperInclusive: true},
+		{Requirement: ">= 1.0, > 1.0, < 3, < 2.5", Lower: "1.0", Upper: "2.5"},
+		{Requirement: "= 1.2.3", Lower: "1.2.3", Upper: "1.2.3", LowerInclusive: true, UpperInclusive: true},
+		{Requirement: ">= 1.0, != 1.5", Lower: "1.0", LowerInclusive: true},
+		{Requirement: "< 2", Upper: "2"},
+		{Requirement: "!= 1.5"},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		lower, upper, lowerInclusive, upperInclusive := req.Bounds()
+
+		if (lower == nil) != (test.Lower == "") || (lower != nil && lower.Version() != test.Lower) {
+			t.Error("expected lower bound of", test.Requirement, "to be", test.Lower, "but got", lower)
+		}
+
+		if (upper == nil) != (test.Upper == "") || (upper != nil && upper.Version() != test.Upper) {
+			t.Error("expected upper bound of", test.Requirement, "to be", test.Upper, "but got", upper)
+		}
+
+		if lowerInclusive != test.LowerInclusive || upperInclusive != test.UpperInclusive {
+			t.Error("expected inclusivity of", test.Requirement, "to be", test.LowerInclusive, test.UpperInclusive, "but got", lowerInclusive, upperInclusive)
+		}
+	}
+}
+
+func Test_FromSpecifiers(t *testing.T) {
+	lower, err := NewSpecifier(">=", version.MustNew("1.2"))
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	upper, err := NewSpecifier("<", version.MustNew("2.0"))
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	req := FromSpecifiers(lower, upper)
+
+	if req.ToString() != ">= 1.2, < 2.0" {
+		t.Error("expected requirement to be >= 1.2, < 2.0 but was", req.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.2": true, "1.9.9": true, "2.0": false, "1.1": false} {
+		if req.IsSatisfiedBy(version.MustNew(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	if _, err := NewSpecifier("=>", version.MustNew("1.0")); !errors.Is(err, ErrMalformedRequirement) {
+		t.Error("expected an invalid operator to return ErrMalformedRequirement but got", err)
+	}
+
+	if _, err := NewSpecifier(">=", nil); err == nil {
+		t.Error("expected a nil version to return an error")
+	}
+}
+
+func Test_SatisfiedByExactlyOne(t *testing.T) {
+	var pool []*version.Version
+	for _, input := range []string{"1.1", "1.5", "2.0", "2.1"} {
+		pool = append(pool, version.New2(input))
+	}
+	pool = append(pool, nil)
+
+	tests := []struct {
+		Requirement string
+		Expected    string
+		OK          bool
+	}{
+		{Requirement: "~> 1.2", Expected: "1.5", OK: true},
+		{Requirement: "= 2.0.0", Expected: "2.0", OK: true},
+		{Requirement: "> 1.0", OK: false},
+		{Requirement: "> 3.0", OK: false},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		match, ok := req.SatisfiedByExactlyOne(pool)
+		if ok != test.OK {
+			t.Error("expected SatisfiedByExactlyOne for", test.Requirement, "to be", test.OK, "but was", ok)
+			continue
+		}
+
+		if !ok && match != nil {
+			t.Error("expected no match for", test.Requirement, "but got", match.Version())
+		}
+
+		if ok && (match == nil || match.Version() != test.Expected) {
+			t.Error("expected", test.Requirement, "to match only", test.Expected, "but got", match)
+		}
+	}
+}
+
+func Test_Negate(t *testing.T) {
+	versions := []string{"0.9", "1.0", "1.0.0", "1.0.1", "1.5.a", "2.0"}
+
+	for _, op := range []string{"=", "!=", ">", "<", ">=", "<="} {
+		spec, err := NewSpecifier(op, version.MustNew("1.0"))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		negated, err := spec.Negate()
+		if err != nil {
+			t.Error("expected", spec, "to be negatable but got", err)
+			continue
+		}
+
+		for _, input := range versions {
+			v := version.MustNew(input)
+			if spec.IsSatisfiedBy(v) == negated.IsSatisfiedBy(v) {
+				t.Error("expected exactly one of", spec, "and", negated, "to be satisfied by", input)
+			}
+		}
+
+		twice, err := negated.Negate()
+		if err != nil || twice.Operator != op {
+			t.Error("expected negating", spec, "twice to give it back but got", twice, err)
+		}
+	}
+
+	spec, _ := NewSpecifier("~>", version.MustNew("1.2"))
+	if _, err := spec.Negate(); err == nil {
+		t.Error("expected negating ~> to return an error")
+	}
+}
+
+func Test_IsSatisfiedByString(t *testing.T) {
+	req, err := New("~> 1.2")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	for input, expected := range map[string]bool{"1.2": true, "v1.9.9": true, "2.0": false, "1.5-3": false} {
+		satisfied, err := req.IsSatisfiedByString(input)
+		if err != nil {
+			t.Error("expected", input, "to parse but got", err)
+			continue
+		}
+
+		if satisfied != expected {
+			t.Error("expected IsSatisfiedByString(", input, ") to be", expected)
+		}
+	}
+
+	satisfied, err := req.IsSatisfiedByString("1.")
+	if !errors.Is(err, version.ErrMalformedVersion) || satisfied {
+		t.Error("expected a malformed version to return false and the parse error but got", satisfied, err)
+	}
+}
+
+func Test_ConcurrentReads(t *testing.T) {
+	req, err := New("~> 1.2, != 1.5")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	v := version.MustNew("1.4")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if !req.IsSatisfiedBy(v) {
+					t.Error("expected", v.Version(), "to satisfy", req)
+					return
+				}
+
+				req.ToString()
+				req.Bounds()
+				req.Simplify()
+				req.Intersect(req)
+				req.Explain(v)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_Tighten(t *testing.T) {
+	req, err := New(">= 1.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	tightened, err := req.Tighten("< 2.0")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if req.ToString() != ">= 1.0" {
+		t.Error("expected the original requirement to be unchanged but was", req.ToString())
+	}
+
+	if tightened.ToString() != ">= 1.0, < 2.0" {
+		t.Error("expected the tightened requirement to be >= 1.0, < 2.0 but was", tightened.ToString())
+	}
+
+	for input, satisfied := range map[string]bool{"1.0": true, "1.9": true, "2.0": false, "2.5": false} {
+		if tightened.IsSatisfiedBy(version.MustNew(input)) != satisfied {
+			t.Error("expected tightened IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	if !req.IsSatisfiedBy(version.MustNew("2.5")) {
+		t.Error("expected the original requirement to still be satisfied by 2.5")
+	}
+
+	if _, err := req.Tighten("< 2."); !errors.Is(err, version.ErrMalformedVersion) && !errors.Is(err, ErrMalformedRequirement) {
+		t.Error("expected a parse error for a malformed specifier but got", err)
+	}
+}
+
+func Test_HasNone(t *testing.T) {
+	tests := map[string]bool{
+		"":          true,
+		"  ":        true,
+		">= 0":      true,
+		">= 0.0":    true,
+		">= 0.a":    true,
+		">= 1.0":    false,
+		"= 0":       false,
+		">= 0, < 1": false,
+	}
+
+	for input, expected := range tests {
+		req, err := New(input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if req.HasNone() != expected {
+			t.Error("expected HasNone() of", input, "to be", expected)
+		}
+	}
+
+	req, _ := New()
+	if !req.HasNone() {
+		t.Error("expected a requirement without specifiers to have none")
+	}
+
+	if !FromSpecifiers(&RequirementSpecifier{Operator: ">=", Version: version.MustNew("0")}).HasNone() {
+		t.Error("expected a separately built >= 0 to have none")
+	}
+
+	for input, expected := range map[string]bool{"": false, ">= 0": false, ">= 0.a": true} {
+		req, _ := New(input)
+		if req.IsSatisfiedBy(version.New2("1.0.pre")) != expected {
+			t.Error("expected IsSatisfiedBy(1.0.pre) of", input, "to be", expected)
+		}
+	}
+}
+
+func Test_Dropped(t *testing.T) {
+	var pool []*version.Version
+	for _, input := range []string{"1.0", "1.4", "1.9", "2.0", "2.5", "3.0"} {
+		pool = append(pool, version.New2(input))
+	}
+	pool = append(pool, nil)
+
+	tests := []struct {
+		Old      string
+		New      string
+		Expected []string
+	}{
+		{Old: ">= 1.0, < 2.0", New: "~> 1.4", Expected: []string{"1.0"}},
+		{Old: "~> 1.4", New: ">= 1.0, < 3.0", Expected: nil},
+		{Old: ">= 1.0", New: "~> 2.0", Expected: []string{"1.0", "1.4", "1.9", "3.0"}},
+	}
+
+	for _, test := range tests {
+		old, err := New(test.Old)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		updated, err := New(test.New)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		var dropped []string
+		for _, v := range Dropped(old, updated, pool) {
+			dropped = append(dropped, v.Version())
+		}
+
+		if fmt.Sprint(dropped) != fmt.Sprint(test.Expected) {
+			t.Error("expected changing", test.Old, "to", test.New, "to drop", test.Expected, "but got", dropped)
+		}
+	}
+}
+
+func Test_Satisfies(t *testing.T) {
+	tests := []struct {
+		Requirement string
+		Other       string
+		Expected    bool
+	}{
+		{Requirement: ">= 2.0", Other: ">= 1.0", Expected: true},
+		{Requirement: ">= 1.0", Other: ">= 2.0", Expected: false},
+		{Requirement: "> 1.0", Other: ">= 1.0", Expected: true},
+		{Requirement: ">= 1.0", Other: "> 1.0", Expected: false},
+		{Requirement: "~> 1.4", Other: ">= 1.0, < 2.0", Expected: true},
+		{Requirement: ">= 1.0, < 2.0", Other: "~> 1.4", Expected: false},
+		{Requirement: "= 1.5", Other: "~> 1.2", Expected: true},
+		{Requirement: "~> 1.2", Other: "^1.2", Expected: true},
+		{Requirement: ">= 1.0, < 2.0", Other: "!= 2.5", Expected: true},
+		{Requirement: ">= 1.0", Other: "!= 1.5", Expected: false},
+		{Requirement: ">= 1.0, != 1.5", Other: "!= 1.5", Expected: true},
+		{Requirement: ">= 1.0, <= 1.0", Other: "= 1.0", Expected: true},
+		{Requirement: ">= 1.0.a", Other: ">= 0.1", Expected: false},
+		{Requirement: "< 3", Other: ">= 0", Expected: true},
+		{Requirement: "< 3", Other: ">= 0.1", Expected: false},
+		{Requirement: ">= 1.0", Other: "< 3", Expected: false},
+	}
+
+	for _, test := range tests {
+		req, err := New(test.Requirement)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		other, err := New(test.Other)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if req.Satisfies(other) != test.Expected {
+			t.Error("expected", test.Requirement, "Satisfies", test.Other, "to be", test.Expected)
+		}
+	}
+}
+
+func Test_Clamp(t *testing.T) {
+	var pool []*version.Version
+	for _, input := range []string{"1.0", "1.4", "1.9", "2.0", "2.5", "3.0"} {
+		pool = append(pool, version.New2(input))
+	}
+	pool = append(pool, nil)
+
+	req, err := New(">= 1.4, < 3.0")
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+		return
+	}
+
+	tests := map[string]string{
+		"0.5": "1.4",
+		"1.5": "1.4",
+		"2.0": "2.0",
+		"9.0": "2.5",
+	}
+
+	for input, expected := range tests {
+		clamped := req.Clamp(version.New2(input), pool)
+		if clamped == nil || clamped.Version() != expected {
+			t.Error("expected", input, "to clamp to", expected, "but got", clamped)
+		}
+	}
+
+	req, _ = New("> 5.0")
+	if clamped := req.Clamp(version.New2("6.0"), pool); clamped != nil {
+		t.Error("expected Clamp to return nil when nothing satisfies the requirement but got", clamped)
+	}
+}
@@ -0,0 +1,87 @@
+package requirement
+
+import (
+	"strings"
+
+	"github.com/robicode/version"
+)
+
+// A RequirementSet is a disjunction of Requirements: it is satisfied when
+// any one of its members is satisfied. Within each member, the
+// specifiers still AND together as usual, so "~> 1.2, != 1.2.5 || >= 3.0"
+// means "(~> 1.2 and != 1.2.5) or >= 3.0".
+type RequirementSet struct {
+	any []*Requirement
+}
+
+// NewSet parses a requirement string containing one or more groups
+// separated by "||". Each group is parsed with New, so it may hold
+// several comma-separated specifiers.
+func NewSet(requirements string) (*RequirementSet, error) {
+	var set RequirementSet
+
+	for _, group := range strings.Split(requirements, "||") {
+		if strings.TrimSpace(group) == "" {
+			return nil, &ErrMalformed{Input: requirements, Reason: "empty requirement between '||'"}
+		}
+
+		req, err := New(strings.TrimSpace(group))
+		if err != nil {
+			return nil, err
+		}
+
+		set.any = append(set.any, req)
+	}
+
+	return &set, nil
+}
+
+// Or returns a *RequirementSet satisfied when any of the given
+// requirements is satisfied. Nil requirements are skipped.
+func Or(requirements ...*Requirement) *RequirementSet {
+	var set RequirementSet
+
+	for _, req := range requirements {
+		if req != nil {
+			set.any = append(set.any, req)
+		}
+	}
+
+	return &set
+}
+
+// Requirements returns the members of the set. The returned slice is a
+// copy, so modifying it does not affect s.
+func (s *RequirementSet) Requirements() []*Requirement {
+	reqs := make([]*Requirement, len(s.any))
+	copy(reqs, s.any)
+
+	return reqs
+}
+
+// IsSatisfiedBy returns true if the given *Version satisfies any member of
+// the set. An empty set is satisfied by nothing.
+func (s *RequirementSet) IsSatisfiedBy(v *version.Version) bool {
+	for _, req := range s.any {
+		if req.IsSatisfiedBy(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String implements fmt.Stringer, joining the members with " || ".
+func (s *RequirementSet) String() string {
+	if s == nil {
+		return ""
+	}
+
+	var groups []string
+
+	for _, req := range s.any {
+		groups = append(groups, req.String())
+	}
+
+	return strings.Join(groups, " || ")
+}
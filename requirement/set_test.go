@@ -0,0 +1,64 @@
+package requirement
+
+import (
+	"testing"
+
+	"github.com/robicode/version"
+)
+
+func Test_NewSet(t *testing.T) {
+	set, err := NewSet("< 2.0 || >= 3.0")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	for input, satisfied := range map[string]bool{"1.5": true, "3.1": true, "2.5": false, "3.0": true, "2.0": false} {
+		if set.IsSatisfiedBy(version.New2(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	set, err = NewSet("~> 1.2, != 1.2.5 || >= 3.0")
+	if err != nil {
+		t.Error("expected err to be nil but got:", err)
+		t.Fail()
+		return
+	}
+
+	if set.String() != "~> 1.2, != 1.2.5 || >= 3.0" {
+		t.Error("unexpected String():", set.String())
+	}
+
+	for input, satisfied := range map[string]bool{"1.2.4": true, "1.2.5": false, "2.1": false, "3.0": true} {
+		if set.IsSatisfiedBy(version.New2(input)) != satisfied {
+			t.Error("expected IsSatisfiedBy(", input, ") to be", satisfied)
+		}
+	}
+
+	for _, input := range []string{"< 2.0 ||", "|| >= 3.0", "< 2.0 || bogus"} {
+		if _, err := NewSet(input); err == nil {
+			t.Error("expected", input, "to be invalid")
+		}
+	}
+}
+
+func Test_Or(t *testing.T) {
+	low, _ := New("< 2.0")
+	high, _ := New(">= 3.0")
+
+	set := Or(low, nil, high)
+
+	if len(set.Requirements()) != 2 {
+		t.Error("expected nil requirements to be skipped")
+	}
+
+	if !set.IsSatisfiedBy(version.New2("1.0")) || set.IsSatisfiedBy(version.New2("2.5")) {
+		t.Error("expected Or to be satisfied by any member")
+	}
+
+	if Or().IsSatisfiedBy(version.New2("1.0")) {
+		t.Error("expected an empty set to be satisfied by nothing")
+	}
+}
@@ -0,0 +1,55 @@
+package version
+
+import "slices"
+
+// A VersionSet is a set of versions in which versions that Compare as
+// equal (e.g. "1.0" and "1") are the same member. The zero value is an
+// empty set ready to use.
+type VersionSet struct {
+	members map[string]*Version
+}
+
+// Add adds v to the set. If an equal version is already a member, the
+// existing member is kept. Nil versions are ignored.
+func (s *VersionSet) Add(v *Version) {
+	if v == nil {
+		return
+	}
+
+	if s.members == nil {
+		s.members = map[string]*Version{}
+	}
+
+	if _, ok := s.members[v.Hash()]; !ok {
+		s.members[v.Hash()] = v
+	}
+}
+
+// Contains returns true if a version equal to v is a member of the set.
+func (s *VersionSet) Contains(v *Version) bool {
+	if v == nil {
+		return false
+	}
+
+	_, ok := s.members[v.Hash()]
+
+	return ok
+}
+
+// Len returns the number of members in the set.
+func (s *VersionSet) Len() int {
+	return len(s.members)
+}
+
+// Sorted returns the members of the set in ascending order.
+func (s *VersionSet) Sorted() []*Version {
+	versions := make([]*Version, 0, len(s.members))
+
+	for _, v := range s.members {
+		versions = append(versions, v)
+	}
+
+	slices.SortFunc(versions, CompareAscending)
+
+	return versions
+}
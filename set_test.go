@@ -0,0 +1,47 @@
+package version
+
+import "testing"
+
+func Test_VersionSet(t *testing.T) {
+	var set VersionSet
+
+	if set.Len() != 0 || set.Contains(New2("1.0")) {
+		t.Error("expected the zero VersionSet to be empty")
+	}
+
+	for _, input := range []string{"1.0", "2.0.a", "1", "1.10", "1.0.0", "1.2", "2.0.a.0"} {
+		set.Add(New2(input))
+	}
+	set.Add(nil)
+
+	if set.Len() != 4 {
+		t.Error("expected 4 members but got", set.Len())
+	}
+
+	if !set.Contains(New2("1")) || !set.Contains(New2("1.0.0.0")) || set.Contains(New2("1.1")) {
+		t.Error("expected Contains to use canonical equality")
+	}
+
+	var prereleases VersionSet
+	prereleases.Add(New2("1.a.01"))
+	prereleases.Add(New2("1.a.1"))
+
+	if prereleases.Len() != 1 {
+		t.Error("expected 1.a.01 and 1.a.1 to be a single member but got", prereleases.Len())
+	}
+
+	expected := []string{"1.0", "1.2", "1.10", "2.0.a"}
+	sorted := set.Sorted()
+
+	if len(sorted) != len(expected) {
+		t.Error("expected", len(expected), "sorted versions but got", len(sorted))
+		t.Fail()
+		return
+	}
+
+	for i, v := range sorted {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+}
@@ -19,8 +19,11 @@ package version
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -48,34 +51,156 @@ import (
 // 3. 1.0.a.2
 // 4. 0.9
 //
+// A version may be prefixed with a Debian/RPM-style epoch (e.g. 1:2.3.4).
+// The epoch dominates the rest of the version when comparing, so 1:1.0
+// is newer than 9.9. Versions without an epoch have an epoch of 0.
+//
+// A version may carry SemVer build metadata after a "+" (e.g.
+// 1.0.0+20130313144700). The metadata is preserved by Version() but is
+// ignored when comparing versions.
+//
+// A *Version is never modified once New has returned it; its segments are
+// computed up front rather than lazily. Its methods are therefore safe for
+// concurrent use by multiple goroutines, with the exception of the
+// decoding methods (GobDecode, UnmarshalBinary, UnmarshalYAML), which
+// overwrite the receiver.
+//
 // For further documentation and background, consult the Ruby Gem::Version docs.
 type Version struct {
-	version string
+	version  string
+	original string
+	build    string
+	epoch    int
+	semver   bool
+
+	// Segments are cached by New, since a Version never changes once it
+	// has been constructed. cached is false for a zero Version, in which
+	// case they are computed on demand.
+	cached    bool
+	segs      []string
+	canonical []string
 }
 
 var (
-	VersionPattern         = `[0-9]+(\.[0-9a-zA-Z]+)*(-[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?`
+	VersionPattern         = `[0-9]+(\.[0-9a-zA-Z]+)*(-[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?(\+[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?`
 	VersionPatternAnchored = fmt.Sprintf(`\A\s*(%s)?\s*\z`, VersionPattern)
 )
 
-// New creates a new *Version with the given version string.
+// alphaPattern matches segments containing letters. It is compiled once
+// since it is used on every comparison.
+var alphaPattern = regexp.MustCompile(`[a-zA-Z]+`)
+
+// segmentPattern matches a single numeric or alphabetic segment.
+var segmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// anchoredPattern is VersionPatternAnchored compiled once, since New and
+// errorPosition match against it for every input.
+var anchoredPattern = regexp.MustCompile(VersionPatternAnchored)
+
+// semverPattern is the official SemVer 2.0.0 grammar.
+var semverPattern = regexp.MustCompile(`\A(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+	`(-(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*))*)?` +
+	`(\+[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?\z`)
+
+// epochPrefix matches a leading "N:" epoch.
+var epochPrefix = regexp.MustCompile(`\A\s*([0-9]+):`)
+
+// versionPrefix matches a single leading "v" or "V" immediately followed
+// by a digit, as commonly found in Git tags (e.g. "v1.2.3").
+var versionPrefix = regexp.MustCompile(`\A(\s*)[vV]([0-9])`)
+
+// New creates a new *Version with the given version string. A single
+// leading "v" or "V" directly followed by a digit is stripped, so
+// "v1.2.3" parses identically to "1.2.3", leading zeros are removed
+// from numeric release segments, so "1.02" is stored as "1.2", and
+// segments mixing letters and digits are split, so "1.0.a10" is stored
+// as "1.0.a.10".
 func New(version string) (*Version, error) {
-	if !isCorrect(version) {
-		return nil, fmt.Errorf("malformed version number string: '%s'", version)
-	}
+	var epoch int
 
 	ver := version
 
-	if regexp.MustCompile(`/\A\s*\z/`).MatchString(version) {
+	if match := epochPrefix.FindStringSubmatch(ver); match != nil {
+		value, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, malformed(version, version)
+		}
+
+		if strings.TrimSpace(ver[len(match[0]):]) == "" {
+			return nil, &ErrMalformed{Input: version, Position: len(version)}
+		}
+
+		epoch = value
+		ver = ver[len(match[0]):]
+	}
+
+	ver = versionPrefix.ReplaceAllString(ver, "${1}${2}")
+
+	if !isCorrect(ver) {
+		return nil, malformed(version, ver)
+	}
+
+	if regexp.MustCompile(`\A\s*\z`).MatchString(ver) {
 		ver = "0"
 	}
 
 	ver = strings.TrimSpace(ver)
+	semver := epoch == 0 && semverPattern.MatchString(ver)
+	ver, build, _ := strings.Cut(ver, "+")
 	ver = strings.ReplaceAll(ver, "-", ".pre.")
+	ver = splitMixedSegments(ver)
+	ver = trimLeadingZeros(ver, false)
+
+	v := &Version{
+		version:  ver,
+		original: version,
+		build:    build,
+		epoch:    epoch,
+		semver:   semver,
+	}
+	v.cacheSegments()
+
+	return v, nil
+}
+
+// trimLeadingZeros removes leading zeros from the numeric release segments
+// of version (e.g. "01.02.0" => "1.2.0") so that the stored string agrees
+// with Compare. Prerelease segments are left untouched unless prerelease
+// is true, in which case their numeric segments are trimmed as well.
+func trimLeadingZeros(version string, prerelease bool) string {
+	parts := strings.Split(version, ".")
 
-	return &Version{
-		version: ver,
-	}, nil
+	for i, part := range parts {
+		if alphaPattern.MatchString(part) {
+			if !prerelease {
+				break
+			}
+
+			continue
+		}
+
+		parts[i] = strings.TrimLeft(part, "0")
+		if parts[i] == "" && part != "" {
+			parts[i] = "0"
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// splitMixedSegments splits any segment mixing letters and digits into
+// separate segments (e.g. "1.0.a10" => "1.0.a.10"), so that the stored
+// string agrees with the segments the version is compared by.
+func splitMixedSegments(version string) string {
+	return strings.Join(segmentPattern.FindAllString(version, -1), ".")
+}
+
+// cacheSegments computes and stores the segments and canonical segments
+// of v so that repeated comparisons don't need to recompute them.
+func (v *Version) cacheSegments() {
+	v.segs = v.segments()
+	v.canonical = v.canonicalSegments()
+	v.cached = true
 }
 
 // New2 returns a new *Version with the given version string or nil on error
@@ -88,6 +213,101 @@ func New2(version string) *Version {
 	return v
 }
 
+// Coerce makes a best-effort attempt to build a *Version from s by
+// extracting the first substring matching VersionPattern. It is
+// deliberately lossy: surrounding text and anything after a "+" are
+// dropped, so "Release 1.2.3-beta+build" coerces to "1.2.3-beta". An error
+// is returned only if s contains no numeric version at all.
+func Coerce(s string) (*Version, error) {
+	input := s
+	if i := strings.Index(input, "+"); i >= 0 {
+		input = input[:i]
+	}
+
+	match := regexp.MustCompile(VersionPattern).FindString(input)
+	if match == "" {
+		return nil, malformed(s, s)
+	}
+
+	return New(match)
+}
+
+// FromSegments builds a version from its numeric release segments and
+// optional prerelease segments, joining them with periods and parsing the
+// result with New, so FromSegments([]int{1, 2, 3}, "rc", "1") is
+// 1.2.3.rc.1. An error is returned if numeric is empty or has a negative
+// segment, or if the result is not a valid version. Each prerelease
+// segment must be non-empty and free of ".", "-" and "+", and the first
+// must start with a letter, so that prerelease cannot extend the release
+// (FromSegments([]int{1, 2}, "3")) or smuggle in build metadata.
+func FromSegments(numeric []int, prerelease ...string) (*Version, error) {
+	if len(numeric) == 0 {
+		return nil, fmt.Errorf("cannot build version: no numeric segments")
+	}
+
+	var segments []string
+
+	for _, n := range numeric {
+		if n < 0 {
+			return nil, fmt.Errorf("cannot build version: negative segment %d", n)
+		}
+
+		segments = append(segments, strconv.Itoa(n))
+	}
+
+	input := strings.Join(append(segments, prerelease...), ".")
+	position := len(strings.Join(segments, "."))
+
+	for i, segment := range prerelease {
+		position++
+
+		if segment == "" || strings.ContainsAny(segment, ".-+") || (i == 0 && !alphaPattern.MatchString(segment[:1])) {
+			return nil, &ErrMalformed{Input: input, Position: position}
+		}
+
+		position += len(segment)
+	}
+
+	return New(input)
+}
+
+// MustNew returns a new *Version with the given version string and
+// panics if it cannot be parsed. It is intended for package-level
+// variables and other initialization code:
+//
+//	var Min = version.MustNew("1.2.0")
+func MustNew(version string) *Version {
+	v, err := New(version)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Clone returns a new *Version equal to v but independent of it. A nil
+// receiver returns nil.
+func (v *Version) Clone() *Version {
+	if v == nil {
+		return nil
+	}
+
+	clone := *v
+
+	return &clone
+}
+
+// derive returns a new version made of segments, carrying over the epoch
+// of v so that derived versions still compare correctly against it.
+func (v *Version) derive(segments []string) (*Version, error) {
+	input := strings.Join(segments, ".")
+	if v.epoch != 0 {
+		input = strconv.Itoa(v.epoch) + ":" + input
+	}
+
+	return New(input)
+}
+
 // Return a new version object where the next to the last revision
 // number is one greater (e.g., 5.3.1 => 5.4).
 //
@@ -96,43 +316,221 @@ func (v *Version) Bump() (*Version, error) {
 	segments := v.segments()
 
 	for i, segment := range segments {
-		if regexp.MustCompile(`[a-zA-Z]+`).MatchString(segment) {
+		if alphaPattern.MatchString(segment) {
 			segments = segments[0:i]
+			break
 		}
 	}
 
-	if len(segments) > 1 {
+	switch len(segments) {
+	case 0:
+		return nil, fmt.Errorf("cannot bump version '%s': no numeric segments", v.version)
+	case 1:
+		// A single-segment version such as "1" bumps its only segment.
+	default:
 		segments = segments[:len(segments)-1]
 	}
 
 	num, err := strconv.Atoi(segments[len(segments)-1])
 	if err != nil {
-		panic("last element string should contain an int")
+		return nil, fmt.Errorf("cannot bump version '%s': %w", v.version, err)
 	}
 
 	num = num + 1
 	segments[len(segments)-1] = strconv.Itoa(num)
 
-	version := strings.Join(segments, ".")
+	return v.derive(segments)
+}
+
+// BumpLast returns a new version with the last numeric release segment
+// incremented, keeping the same number of segments (e.g. 1.2.3 => 1.2.4,
+// where Bump would give 1.3). Any prerelease part is dropped, so
+// 1.2.3.b.2 => 1.2.4.
+func (v *Version) BumpLast() (*Version, error) {
+	numerics, _ := v.splitSegments()
+	if len(numerics) == 0 {
+		return nil, fmt.Errorf("cannot bump version '%s': no numeric segments", v.version)
+	}
+
+	return v.IncrementAt(len(numerics) - 1)
+}
+
+// IncrementAt returns a new version with the numeric segment at the given
+// zero-based index incremented, all later numeric segments set to zero,
+// and any prerelease part dropped (e.g. IncrementAt(1) on 1.2.3.4 =>
+// 1.3.0.0). An error is returned if index is out of range or refers to a
+// prerelease segment.
+func (v *Version) IncrementAt(index int) (*Version, error) {
+	numerics, stringset := v.splitSegments()
+
+	if index < 0 || index >= len(numerics)+len(stringset) {
+		return nil, fmt.Errorf("cannot increment version '%s': index %d out of range", v.version, index)
+	}
+
+	if index >= len(numerics) {
+		return nil, fmt.Errorf("cannot increment version '%s': index %d is a prerelease segment", v.version, index)
+	}
+
+	value, ok := new(big.Int).SetString(numerics[index], 10)
+	if !ok {
+		return nil, fmt.Errorf("cannot increment version '%s': segment %d is not numeric", v.version, index)
+	}
+
+	numerics[index] = value.Add(value, big.NewInt(1)).String()
+
+	for i := index + 1; i < len(numerics); i++ {
+		numerics[i] = "0"
+	}
+
+	return v.derive(numerics)
+}
+
+// IsImmediateSuccessorOf returns true if v is the next release after o at
+// some level, i.e. if v equals o with one of its numeric segments
+// incremented and all later segments zeroed (IncrementAt). So 1.2.4, 1.3.0
+// and 2 all immediately succeed 1.2.3, while 1.2.5 and 1.3.1 do not.
+// Precision is ignored, so 1.3 succeeds 1.2.3 just as 1.3.0 does. Use
+// IsImmediateSuccessorAt to require a particular level.
+func (v *Version) IsImmediateSuccessorOf(o *Version) bool {
+	numerics, _ := o.splitSegments()
+
+	for i := range numerics {
+		if v.IsImmediateSuccessorAt(o, i) {
+			return true
+		}
+	}
 
-	ver, err := New(version)
+	return false
+}
+
+// IsImmediateSuccessorAt is like IsImmediateSuccessorOf, but only
+// considers incrementing the numeric segment at the given zero-based
+// index (0 for major, 1 for minor, 2 for patch). It returns false if o has
+// no numeric segment at index.
+func (v *Version) IsImmediateSuccessorAt(o *Version, index int) bool {
+	next, err := o.IncrementAt(index)
+	if err != nil {
+		return false
+	}
+
+	return v.Compare(next) == 0
+}
+
+// Truncate returns a new version made of the first n segments of v, so
+// Truncate(2) on 1.2.3.4 => 1.2. Numeric and prerelease segments are
+// treated alike, so truncating into the middle of a prerelease is allowed
+// and keeps the leading part of it (Truncate(3) on 1.0.pre.3 => 1.0.pre).
+// Build metadata is dropped. An error is returned if n is less than 1 or
+// greater than the number of segments.
+func (v *Version) Truncate(n int) (*Version, error) {
+	segments := v.segments()
+
+	if n < 1 || n > len(segments) {
+		return nil, fmt.Errorf("cannot truncate version '%s' to %d segments", v.version, n)
+	}
+
+	return v.derive(segments[:n])
+}
+
+// WithPrerelease returns a new version with the release portion of v and
+// the given prerelease label, replacing any existing prerelease (e.g.
+// WithPrerelease("pre.1") on 1.2.0 => 1.2.0.pre.1). An error is returned
+// if the label would not produce a valid prerelease version.
+func (v *Version) WithPrerelease(label string) (*Version, error) {
+	numerics, _ := v.splitSegments()
+	input := strings.Join(numerics, ".") + "." + label
+
+	if label == "" || strings.Contains(label, "+") {
+		return nil, malformed(input, input)
+	}
+
+	ver, err := v.derive(append(numerics, label))
 	if err != nil {
 		return nil, err
 	}
 
+	if !ver.IsPrerelease() {
+		return nil, malformed(input, input)
+	}
+
 	return ver, nil
 }
 
+// NextPrerelease returns a new version with the last numeric segment of
+// the prerelease portion incremented (e.g. 1.2.0.pre.1 => 1.2.0.pre.2).
+// A prerelease without a trailing number gains one (1.2.0.beta =>
+// 1.2.0.beta.1), and a release starts a new prerelease (1.2.0 =>
+// 1.2.0.pre.1). The release portion is left untouched.
+func (v *Version) NextPrerelease() (*Version, error) {
+	numerics, stringset := v.splitSegments()
+
+	switch {
+	case len(stringset) == 0:
+		stringset = []string{"pre", "1"}
+	case extractKind(stringset[len(stringset)-1]) == reflect.Int:
+		last, ok := new(big.Int).SetString(stringset[len(stringset)-1], 10)
+		if !ok {
+			return nil, fmt.Errorf("cannot increment prerelease of version '%s'", v.version)
+		}
+
+		stringset[len(stringset)-1] = last.Add(last, big.NewInt(1)).String()
+	default:
+		stringset = append(stringset, "1")
+	}
+
+	return v.derive(append(numerics, stringset...))
+}
+
+// IsValid returns true if s is a valid version string, i.e. if New(s)
+// would succeed. Unlike New it doesn't allocate a *Version, so it is the
+// cheaper choice when only validity matters.
+func IsValid(s string) bool {
+	if match := epochPrefix.FindStringSubmatch(s); match != nil {
+		if _, err := strconv.Atoi(match[1]); err != nil {
+			return false
+		}
+
+		s = s[len(match[0]):]
+		if strings.TrimSpace(s) == "" {
+			return false
+		}
+	}
+
+	return isCorrect(versionPrefix.ReplaceAllString(s, "${1}${2}"))
+}
+
 // isCorrect validates the format of the version string.
 func isCorrect(version string) bool {
-	re := regexp.MustCompile(VersionPatternAnchored)
+	return anchoredPattern.MatchString(version)
+}
+
+// Segments returns the version split into its numeric and alphabetic
+// component parts (e.g. "1.0.a10" -> ["1", "0", "a", "10"]).
+func (v *Version) Segments() []string {
+	return v.segments()
+}
+
+// NumSegments returns the number of segments in the version, i.e.
+// len(v.Segments()), counting numeric and alphabetic parts separately
+// ("1.0.a10" has 4). Trailing zeros are counted, so "1.0" has 2.
+func (v *Version) NumSegments() int {
+	if v.cached {
+		return len(v.segs)
+	}
 
-	return re.MatchString(version)
+	return len(v.segments())
 }
 
 // segments splits the version string into its component parts.
 func (v *Version) segments() []string {
-	results := regexp.MustCompile(`[0-9]+|[a-zA-Z]+`).FindAllString(v.version, -1)
+	if v.cached {
+		segments := make([]string, len(v.segs))
+		copy(segments, v.segs)
+
+		return segments
+	}
+
+	results := segmentPattern.FindAllString(v.version, -1)
 	if len(results) > 0 {
 		return results
 	}
@@ -146,6 +544,40 @@ func (v *Version) IsPrerelease() bool {
 	return regexp.MustCompile(`[a-zA-Z]`).MatchString(v.version)
 }
 
+// Prerelease returns the prerelease portion of the version, i.e. every
+// segment from the first alphabetic one onward, joined by periods. For
+// "1.5.pre.3" this is "pre.3", and for "1.0.a10" it is "a.10". Release
+// versions return an empty string.
+func (v *Version) Prerelease() string {
+	_, stringset := v.splitSegments()
+
+	return strings.Join(stringset, ".")
+}
+
+// PrereleaseSegments returns the prerelease portion of the version (every
+// segment from the first alphabetic one onward) with numeric segments as
+// int and alphabetic segments as string, which is the basis Compare uses
+// for ordering them. For "1.0.a.10" this is ["a", 10]. Release versions
+// return an empty slice. Numeric segments too large for an int are
+// returned as strings.
+func (v *Version) PrereleaseSegments() []interface{} {
+	_, stringset := v.splitSegments()
+	segments := make([]interface{}, 0, len(stringset))
+
+	for _, segment := range stringset {
+		if extractKind(segment) == reflect.Int {
+			if value, err := strconv.Atoi(segment); err == nil {
+				segments = append(segments, value)
+				continue
+			}
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
 // The release for this version (e.g. 1.2.0.a -> 1.2.0).
 // Non-prerelease versions return themselves.
 func (v *Version) Release() *Version {
@@ -156,52 +588,101 @@ func (v *Version) Release() *Version {
 	segments := v.segments()
 
 	for i, segment := range segments {
-		if regexp.MustCompile(`[a-zA-Z]+`).MatchString(segment) {
+		if alphaPattern.MatchString(segment) {
 			segments = segments[0:i]
+			break
 		}
 	}
 
-	newVersion, err := New(strings.Join(segments, "."))
+	newVersion, err := v.derive(segments)
 	if err != nil {
 		return nil
 	}
 
 	return newVersion
+}
+
+// EqlCanonical is like Eql, but compares canonical segments so that
+// versions specified to different precisions are equal ("1", "1.0" and
+// "1.0.0" are all EqlCanonical). Eql itself stays precision-sensitive for
+// RubyGems compatibility.
+func (v *Version) EqlCanonical(other *Version) bool {
+	return v.Hash() == other.Hash()
+}
+
+// EqualString parses s with New and reports whether it is EqlCanonical to
+// v, so "1.0" equals "1" and "v1.0.0". The parse error is returned if s is
+// not a valid version.
+func (v *Version) EqualString(s string) (bool, error) {
+	other, err := New(s)
+	if err != nil {
+		return false, err
+	}
+
+	return v.EqlCanonical(other), nil
+}
 
-	// return strings.Join(segments, ".")
+// IsZero returns true if v is the zero version, i.e. it compares equal
+// to "0". This includes "0.0.0" and the blank version "", but not "1:0",
+// whose epoch makes it newer than any version without one.
+func (v *Version) IsZero() bool {
+	return v.epoch == 0 && len(v.canonicalSegments()) == 0
 }
 
 // A Version is only Eql() to another version if it's specified to the
 // same precision. Version "1.0" is not the same as version "1".
 func (v *Version) Eql(other *Version) bool {
-	return v.version == other.version
+	return v.epoch == other.epoch && v.version == other.version
 }
 
 // A recommended version for use with a ~> Requirement
 func (v *Version) ApproximateRecommendation() string {
-	segments := v.segments()
+	return v.ApproximateRecommendationAt(2)
+}
 
-	for i, segment := range segments {
-		if regexp.MustCompile(`[a-zA-Z]+`).MatchString(segment) {
-			segments = segments[0:i]
+// ApproximateRecommendationAt is like ApproximateRecommendation, but keeps
+// the given number of leading release segments instead of two, so 3 gives
+// a SemVer-style "~> X.Y.Z". Missing segments are padded with zeros, and a
+// count below 1 is treated as 1. A non-zero epoch is kept, so 1:1.3.5
+// gives "~> 1:1.3".
+func (v *Version) ApproximateRecommendationAt(segments int) string {
+	if segments < 1 {
+		segments = 1
+	}
+
+	release := v.segments()
+
+	for i, segment := range release {
+		if alphaPattern.MatchString(segment) {
+			release = release[0:i]
+			break
 		}
 	}
 
-	if len(segments) > 2 {
-		segments = segments[0:2]
+	if len(release) > segments {
+		release = release[0:segments]
 	}
 
-	if len(segments) < 2 {
-		segments = append(segments, "0")
+	for len(release) < segments {
+		release = append(release, "0")
 	}
 
-	recommendation := "~> " + strings.Join(segments, ".")
+	recommendation := strings.Join(release, ".")
+	if v.epoch != 0 {
+		recommendation = strconv.Itoa(v.epoch) + ":" + recommendation
+	}
 
 	if v.IsPrerelease() {
 		recommendation += ".a"
 	}
 
-	return recommendation
+	return "~> " + recommendation
+}
+
+// SplitSegments returns the segments split into the leading numeric
+// segments and the remaining prerelease segments.
+func (v *Version) SplitSegments() (numeric, alpha []string) {
+	return v.splitSegments()
 }
 
 // splitSegments splits the segments into integer and alphanumeric arrays.
@@ -210,7 +691,7 @@ func (v *Version) splitSegments() ([]string, []string) {
 	segments := v.segments()
 
 	for i, v := range segments {
-		if regexp.MustCompile(`[a-zA-Z]+`).MatchString(v) {
+		if alphaPattern.MatchString(v) {
 			stringStart = i
 			break
 		}
@@ -220,8 +701,10 @@ func (v *Version) splitSegments() ([]string, []string) {
 		stringStart = len(segments)
 	}
 
+	// Cap the numeric slice so that appending to it can't overwrite the
+	// prerelease segments that share its backing array.
 	stringElements := segments[stringStart:]
-	numericSegments := segments[0:stringStart]
+	numericSegments := segments[0:stringStart:stringStart]
 
 	return numericSegments, stringElements
 }
@@ -235,40 +718,154 @@ func reverseSlice(s interface{}) {
 	}
 }
 
-// canonicalSegments is like segments, but with trailing zero segments removed.
-func (v *Version) canonicalSegments() []string {
-	var flattened []string
+// CanonicalSegments is like Segments, but with trailing zero segments
+// removed from both the numeric and prerelease parts.
+func (v *Version) CanonicalSegments() []string {
+	canonical := v.canonicalSegments()
+	segments := make([]string, len(canonical))
+	copy(segments, canonical)
 
-	numerics, stringset := v.splitSegments()
+	return segments
+}
+
+// Canonical returns the version with trailing zero segments removed, as
+// used by Compare, so that "1.0" and "1.0.0" both become "1". Leading
+// zeros are dropped from numeric prerelease segments too, since Compare
+// ignores them ("1.a.01" becomes "1.a.1"). A version made up only of
+// zeros returns "0", and a "0" release is kept in front of a prerelease
+// with no other release segments ("0.0.pre.1" becomes "0.pre.1") so that
+// the result can be parsed by New. A non-zero epoch is kept as a prefix,
+// and build metadata is not included.
+func (v *Version) Canonical() string {
+	canonical := "0"
+	if segments := v.canonicalSegments(); len(segments) > 0 {
+		canonical = trimLeadingZeros(strings.Join(segments, "."), true)
+
+		if alphaPattern.MatchString(segments[0]) {
+			canonical = "0." + canonical
+		}
+	}
 
-	reverseSlice(numerics)
-	reverseSlice(stringset)
+	if v.epoch != 0 {
+		canonical = strconv.Itoa(v.epoch) + ":" + canonical
+	}
 
-	for _, v := range numerics {
-		value, _ := strconv.Atoi(v)
+	return canonical
+}
 
-		if value == 0 {
-			numerics = deleteArrayElement(numerics, 0)
-		} else {
+// Normalize parses s with New and returns its Canonical form, so that
+// strings which Compare as equal normalize to identical text (e.g.
+// "1.5-3", "1.5.pre.3" and "v1.5.0-3" all become "1.5.pre.3").
+func Normalize(s string) (string, error) {
+	v, err := New(s)
+	if err != nil {
+		return "", err
+	}
+
+	return v.Canonical(), nil
+}
+
+// EquivalentForms parses s with New and returns a set of spellings that
+// all Compare equal to it, starting with its Canonical form. The set is
+// made up of:
+//
+//   - the Canonical form (e.g. "1.5.pre.3"),
+//   - the release padded with trailing zeros to three segments (e.g.
+//     "1.5.0.pre.3"), and
+//   - each of the above with ".pre." spelled as "-" (e.g. "1.5-3").
+//
+// A non-zero epoch is kept as a prefix on every form, and build metadata
+// is dropped. The set is not exhaustive: leading zeros, a "v" prefix and
+// padding beyond three segments are also accepted by New.
+func EquivalentForms(s string) ([]string, error) {
+	v, err := New(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []string
+	if canonical := v.canonicalSegments(); len(canonical) > 0 {
+		segments = strings.Split(trimLeadingZeros(strings.Join(canonical, "."), true), ".")
+	}
+
+	release := []string{}
+	for _, segment := range segments {
+		if alphaPattern.MatchString(segment) {
 			break
 		}
+
+		release = append(release, segment)
 	}
 
-	for _, v := range stringset {
-		value, err := strconv.Atoi(v)
-		if err != nil {
-			break
+	prerelease := segments[len(release):]
+
+	if len(release) == 0 {
+		release = append(release, "0")
+	}
+
+	candidates := []string{strings.Join(append(release, prerelease...), ".")}
+
+	for len(release) < 3 {
+		release = append(release, "0")
+		candidates = append(candidates, strings.Join(append(release, prerelease...), "."))
+	}
+
+	for _, candidate := range candidates {
+		if strings.Contains(candidate, ".pre.") {
+			candidates = append(candidates, strings.ReplaceAll(candidate, ".pre.", "-"))
 		}
+	}
 
-		if value == 0 {
-			stringset = deleteArrayElement(stringset, 0)
-		} else {
-			break
+	var forms []string
+
+	for _, candidate := range candidates {
+		if v.epoch != 0 {
+			candidate = strconv.Itoa(v.epoch) + ":" + candidate
+		}
+
+		if slices.Contains(forms, candidate) {
+			continue
+		}
+
+		if equivalent, err := New(candidate); err == nil && equivalent.Compare(v) == 0 {
+			forms = append(forms, candidate)
 		}
 	}
 
-	reverseSlice(numerics)
-	reverseSlice(stringset)
+	return forms, nil
+}
+
+// Hash returns a key that is the same for all versions that Compare as
+// equal, so "1", "1.0" and "1.0.0" share a hash. This makes it suitable
+// for deduplicating versions in a map. Unlike Eql, it is intentionally
+// insensitive to precision.
+func (v *Version) Hash() string {
+	return v.Canonical()
+}
+
+// canonicalSegments is like segments, but with trailing zero segments removed.
+// The returned slice may be shared with v and must not be modified.
+func (v *Version) canonicalSegments() []string {
+	if v.cached {
+		return v.canonical
+	}
+
+	var flattened []string
+
+	numerics, stringset := v.splitSegments()
+
+	n := len(numerics)
+	for n > 0 && isZeroSegment(numerics[n-1]) {
+		n--
+	}
+
+	m := len(stringset)
+	for m > 0 && isZeroSegment(stringset[m-1]) {
+		m--
+	}
+
+	numerics = numerics[:n]
+	stringset = stringset[:m]
 
 	flattened = append(flattened, numerics...)
 	flattened = append(flattened, stringset...)
@@ -276,9 +873,180 @@ func (v *Version) canonicalSegments() []string {
 	return flattened
 }
 
-// Version returns the version as a string
+// isZeroSegment reports whether segment is numeric and equal to zero.
+func isZeroSegment(segment string) bool {
+	return segment != "" && strings.Trim(segment, "0") == ""
+}
+
+// Major returns the first numeric segment of the version, or 0 if absent.
+// Only the leading numeric segments are considered; prerelease segments
+// are ignored, so "1.5.pre.3" has a Major of 1. Like Minor and Patch, a
+// segment too large for an int is clamped to math.MaxInt; Segments holds
+// its exact value.
+func (v *Version) Major() int {
+	return v.numericSegmentAt(0)
+}
+
+// Minor returns the second numeric segment of the version, or 0 if absent.
+// Only the leading numeric segments are considered, so "1.5.pre.3" has a
+// Minor of 5.
+func (v *Version) Minor() int {
+	return v.numericSegmentAt(1)
+}
+
+// Patch returns the third numeric segment of the version, or 0 if absent.
+// Only the leading numeric segments are considered, so "1.5.pre.3" has a
+// Patch of 0.
+func (v *Version) Patch() int {
+	return v.numericSegmentAt(2)
+}
+
+// IsReleaseBoundary returns true if v is a ".0" release: not a
+// prerelease, and every numeric segment after the minor is zero. So
+// "2.0", "2.0.0" and "2.3.0" are boundaries, while "2.0.1" and "2.0.0.pre"
+// are not.
+func (v *Version) IsReleaseBoundary() bool {
+	if v.IsPrerelease() {
+		return false
+	}
+
+	// Canonical segments have trailing zeros removed, so any segment left
+	// after the minor is non-zero.
+	return len(v.canonicalSegments()) <= 2
+}
+
+// ToMap returns the components of the version for use in templates and
+// structured logs: "major", "minor" and "patch" as int, "prerelease" as
+// returned by Prerelease, and "original" holding the input string as
+// returned by Original. Components missing from the version are 0 or "".
+// As in PrereleaseSegments, numeric components too large for an int are
+// returned as strings.
+func (v *Version) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"major":      v.numericComponentAt(0),
+		"minor":      v.numericComponentAt(1),
+		"patch":      v.numericComponentAt(2),
+		"prerelease": v.Prerelease(),
+		"original":   v.Original(),
+	}
+}
+
+// numericSegmentAt returns the leading numeric segment at index i as an
+// int, or 0 if there is no such segment. A segment too large for an int
+// is clamped to math.MaxInt.
+func (v *Version) numericSegmentAt(i int) int {
+	numerics, _ := v.splitSegments()
+	if i >= len(numerics) {
+		return 0
+	}
+
+	value, err := strconv.Atoi(numerics[i])
+	if err != nil {
+		// The segment is all digits, so this can only be a range error.
+		return math.MaxInt
+	}
+
+	return value
+}
+
+// numericComponentAt is like numericSegmentAt, but returns a segment too
+// large for an int as a string instead of clamping it.
+func (v *Version) numericComponentAt(i int) interface{} {
+	numerics, _ := v.splitSegments()
+	if i < len(numerics) {
+		if _, err := strconv.Atoi(numerics[i]); err != nil {
+			return numerics[i]
+		}
+	}
+
+	return v.numericSegmentAt(i)
+}
+
+// Version returns the version as a string, including any build metadata.
 func (v *Version) Version() string {
-	return v.version
+	ver := v.version
+
+	if v.epoch != 0 {
+		ver = strconv.Itoa(v.epoch) + ":" + ver
+	}
+
+	if v.build != "" {
+		ver += "+" + v.build
+	}
+
+	return ver
+}
+
+// Original returns the string v was created from, exactly as it was
+// passed to New, including any whitespace, "v" prefix or hyphens. Use it
+// to echo the caller's spelling back, e.g. in error messages; Version
+// returns the normalized form. Versions derived from v, such as by Bump or
+// Release, have their own original string, and the zero Version has an
+// empty one.
+func (v *Version) Original() string {
+	return v.original
+}
+
+// IsSemVer returns true if the version was given in strict SemVer 2.0.0
+// form: MAJOR.MINOR.PATCH without leading zeros, with an optional
+// "-prerelease" and "+build". Forms accepted by RubyGems but not SemVer,
+// such as "1.5-3" or "1.0.0.a", return false, as do versions with an
+// epoch. A leading "v" is ignored.
+func (v *Version) IsSemVer() bool {
+	return v.semver
+}
+
+// ToGoSemver returns the version in the "vMAJOR.MINOR.PATCH[-pre][+build]"
+// form accepted by golang.org/x/mod/semver, and true, if it can be
+// represented there. Missing minor and patch segments are filled with
+// zeros (1.2 => v1.2.0), and the prerelease segments become dot-separated
+// identifiers (1.0.0.rc1 => v1.0.0-rc.1). As New stores a hyphen as
+// ".pre.", 1.0.0-rc1 becomes v1.0.0-pre.rc.1. It returns false
+// for versions with more than three numeric segments, an epoch, or a
+// numeric prerelease identifier with a leading zero, none of which SemVer
+// can express.
+func (v *Version) ToGoSemver() (string, bool) {
+	if v.epoch != 0 {
+		return "", false
+	}
+
+	numerics, stringset := v.splitSegments()
+	if len(numerics) > 3 {
+		return "", false
+	}
+
+	for len(numerics) < 3 {
+		numerics = append(numerics, "0")
+	}
+
+	semver := "v" + strings.Join(numerics, ".")
+
+	for _, identifier := range stringset {
+		if len(identifier) > 1 && identifier[0] == '0' && !alphaPattern.MatchString(identifier) {
+			return "", false
+		}
+	}
+
+	if len(stringset) > 0 {
+		semver += "-" + strings.Join(stringset, ".")
+	}
+
+	if v.build != "" {
+		semver += "+" + v.build
+	}
+
+	return semver, true
+}
+
+// Epoch returns the epoch of the version, or 0 if it has none.
+func (v *Version) Epoch() int {
+	return v.epoch
+}
+
+// Build returns the build metadata of the version (the part after a "+"),
+// or an empty string if there is none.
+func (v *Version) Build() string {
+	return v.build
 }
 
 // deleteArrayElement deletes the given element from the given []string.
@@ -300,13 +1068,61 @@ func deleteArrayElement(arr []string, elem int) []string {
 
 // Compare Compares this version with +other+ returning -1, 0, or 1 if the
 // other version is larger, the same, or smaller than this
-// one. Attempts to compare to something that's not a
-// <tt>Gem::Version</tt> return +nil+.
+// one. Unlike Ruby, where comparing to something that's not a
+// <tt>Gem::Version</tt> returns +nil+, Compare panics if o is nil; use
+// SafeCompare to get an error instead.
 func (v *Version) Compare(o *Version) int {
-	l := v.canonicalSegments()
-	r := o.canonicalSegments()
+	return v.compare(o, false)
+}
 
-	if v.version == o.version || strArraysEqual(l, r) {
+// SafeCompare is like Compare, but returns ErrNilVersion instead of
+// panicking if v or o is nil.
+func (v *Version) SafeCompare(o *Version) (int, error) {
+	if v == nil || o == nil {
+		return 0, ErrNilVersion
+	}
+
+	return v.Compare(o), nil
+}
+
+// ComparePrecise is like Compare, but also reports whether v and o are
+// given to the same precision, i.e. have the same number of segments. So
+// "1.0" and "1" compare as 0 but not samePrecision, while "1.2.3" and
+// "1.2.3" are both.
+func (v *Version) ComparePrecise(o *Version) (cmp int, samePrecision bool) {
+	return v.Compare(o), v.NumSegments() == o.NumSegments()
+}
+
+// CompareFold is like Compare, but compares prerelease labels case
+// insensitively, so "1.0.RC1" and "1.0.rc1" compare equal. Compare stays
+// case-sensitive for RubyGems fidelity, where "RC" sorts before "rc".
+func (v *Version) CompareFold(o *Version) int {
+	return v.compare(o, true)
+}
+
+// compare implements Compare and CompareFold. If fold is true alphabetic
+// segments are lowercased before they are compared.
+func (v *Version) compare(o *Version, fold bool) int {
+	if v.epoch != o.epoch {
+		if v.epoch > o.epoch {
+			return 1
+		}
+
+		return -1
+	}
+
+	if v.version == o.version {
+		return 0
+	}
+
+	return compareSegments(v.canonicalSegments(), o.canonicalSegments(), fold)
+}
+
+// compareSegments compares two sets of canonical segments, returning -1,
+// 0, or 1. If fold is true alphabetic segments are lowercased before they
+// are compared.
+func compareSegments(l, r []string, fold bool) int {
+	if strArraysEqual(l, r) {
 		return 0
 	}
 
@@ -338,6 +1154,10 @@ func (v *Version) Compare(o *Version) int {
 			ri = "0"
 		}
 
+		if fold {
+			li, ri = strings.ToLower(li), strings.ToLower(ri)
+		}
+
 		if li == ri {
 			continue
 		}
@@ -351,26 +1171,216 @@ func (v *Version) Compare(o *Version) int {
 		}
 
 		if extractKind(li) == reflect.String && extractKind(ri) == reflect.String {
-			continue
-		}
-
-		lint, _ := strconv.Atoi(li)
-		rint, _ := strconv.Atoi(ri)
+			if li < ri {
+				return -1
+			}
 
-		if lint > rint {
 			return 1
 		}
 
-		return -1
+		if result := compareNumeric(li, ri); result != 0 {
+			return result
+		}
 	}
 
 	return 0
 }
 
+// IsOlderThan returns true if v is an earlier release than o, i.e. v
+// sorts before o. Prereleases are older than their final release.
+func (v *Version) IsOlderThan(o *Version) bool {
+	return v.Compare(o) < 0
+}
+
+// IsNewerThan returns true if v is a later release than o, i.e. v sorts
+// after o.
+func (v *Version) IsNewerThan(o *Version) bool {
+	return v.Compare(o) > 0
+}
+
+// Between returns true if v lies in the half-open interval [low, high):
+// low is inclusive and high is exclusive. A nil low means there is no
+// lower bound and a nil high means there is no upper bound.
+func (v *Version) Between(low, high *Version) bool {
+	if low != nil && v.Compare(low) < 0 {
+		return false
+	}
+
+	if high != nil && v.Compare(high) >= 0 {
+		return false
+	}
+
+	return true
+}
+
+// DiffLevel reports the highest-order level at which v and o differ:
+// "major", "minor", "patch", "prerelease", or "none". Differences in the
+// fourth or later numeric segment are reported as "patch", and versions
+// with the same release but a different prerelease part (e.g. 1.0.0 and
+// 1.0.0.pre.1) report "prerelease". A different epoch dominates the rest
+// of the version, as it does in Compare, so it is reported as "major".
+// Build metadata is ignored.
+func (v *Version) DiffLevel(o *Version) string {
+	if v.epoch != o.epoch {
+		return "major"
+	}
+
+	levels := []string{"major", "minor", "patch"}
+
+	l, _ := v.splitSegments()
+	r, _ := o.splitSegments()
+
+	limit := len(l)
+	if len(r) > limit {
+		limit = len(r)
+	}
+
+	for i := 0; i < limit; i++ {
+		li, ri := "0", "0"
+
+		if i < len(l) {
+			li = l[i]
+		}
+
+		if i < len(r) {
+			ri = r[i]
+		}
+
+		if compareNumeric(li, ri) != 0 {
+			if i < len(levels) {
+				return levels[i]
+			}
+
+			return levels[len(levels)-1]
+		}
+	}
+
+	if !strArraysEqual(v.canonicalSegments(), o.canonicalSegments()) {
+		return "prerelease"
+	}
+
+	return "none"
+}
+
+// CompareAscending returns a negative number when a is an earlier release
+// than b, zero when they are equal, and a positive number when a is a
+// later release. It is equivalent to a.Compare(b), packaged as a function
+// so it can be passed directly to slices.SortFunc:
+//
+//	slices.SortFunc(versions, version.CompareAscending)
+func CompareAscending(a, b *Version) int {
+	return a.Compare(b)
+}
+
+// compareNumeric compares two numeric segments, returning -1, 0, or 1.
+// Segments are compared as arbitrary-precision integers so that very
+// large segments (e.g. date stamps) do not overflow.
+func compareNumeric(a, b string) int {
+	left, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		left = new(big.Int)
+	}
+
+	right, ok := new(big.Int).SetString(b, 10)
+	if !ok {
+		right = new(big.Int)
+	}
+
+	return left.Cmp(right)
+}
+
+// Order is the result of comparing two versions. Its underlying value
+// has the same sign as the result of Compare.
+type Order int
+
+const (
+	Less    Order = -1
+	Equal   Order = 0
+	Greater Order = 1
+)
+
+// String returns the name of the Order.
+func (o Order) String() string {
+	switch o {
+	case Less:
+		return "Less"
+	case Equal:
+		return "Equal"
+	case Greater:
+		return "Greater"
+	}
+
+	return fmt.Sprintf("Order(%d)", int(o))
+}
+
+// Ordering is the result of CompareOrdering, naming the relationship
+// between two releases rather than their numeric order.
+type Ordering int
+
+const (
+	// Older means the receiver is the earlier release.
+	Older Ordering = -1
+	// Same means both versions compare equal.
+	Same Ordering = 0
+	// Newer means the receiver is the later release.
+	Newer Ordering = 1
+)
+
+// String returns the name of the Ordering.
+func (o Ordering) String() string {
+	switch o {
+	case Older:
+		return "Older"
+	case Same:
+		return "Same"
+	case Newer:
+		return "Newer"
+	}
+
+	return fmt.Sprintf("Ordering(%d)", int(o))
+}
+
+// CompareOrdering is like Compare, but returns a named Ordering: Older if
+// v is an earlier release than o, Same if they compare equal, and Newer if
+// v is a later release.
+func (v *Version) CompareOrdering(o *Version) Ordering {
+	return Ordering(v.Compare(o))
+}
+
+// Cmp parses the version strings a and b and reports whether a is Less
+// than, Equal to, or Greater than b. An error is returned if either
+// string is not a valid version.
+func Cmp(a, b string) (Order, error) {
+	left, err := New(a)
+	if err != nil {
+		return Equal, err
+	}
+
+	right, err := New(b)
+	if err != nil {
+		return Equal, err
+	}
+
+	return Order(left.Compare(right)), nil
+}
+
+// CompareString parses the version strings a and b and returns -1, 0, or
+// 1 if a is smaller than, the same as, or larger than b, with the same
+// meaning as a.Compare(b). If either string is malformed, 0 and the parse
+// error are returned.
+func CompareString(a, b string) (int, error) {
+	order, err := Cmp(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(order), nil
+}
+
 // extractKind determines the underlying reflect.Kind of a string.
 // Since wwe only deal with ints and strings, test just those two cases.
 func extractKind(s string) reflect.Kind {
-	if regexp.MustCompile(`[a-zA-Z]+`).MatchString(s) {
+	if alphaPattern.MatchString(s) {
 		return reflect.String
 	}
 
@@ -1,7 +1,14 @@
 package version
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -102,6 +109,22 @@ func Test_IsCorrect(t *testing.T) {
 	}
 }
 
+// IsValid agrees with New on whether a version string is valid.
+func Test_IsValid(t *testing.T) {
+	for _, test := range versionTests {
+		if IsValid(test.Version) != test.ExpectedResponse {
+			t.Error("expected IsValid(", test.Version, ") to be", test.ExpectedResponse)
+		}
+	}
+
+	for _, input := range []string{"v1.2.3", "1:2.0", " 1.0 ", "", "1.0+build.5", "1:", "v", "1..2"} {
+		_, err := New(input)
+		if IsValid(input) != (err == nil) {
+			t.Error("expected IsValid(", input, ") to be", err == nil)
+		}
+	}
+}
+
 // Compare Compares this version with +other+ returning -1, 0, or 1 if the
 // other version is larger, the same, or smaller than this
 // one. Attempts to compare to something that's not a
@@ -190,6 +213,28 @@ func Test_Compare(t *testing.T) {
 	}
 }
 
+// Compare orders differing prerelease labels lexically, as RubyGems does,
+// rather than treating them as equal.
+func Test_ComparePrereleaseLabels(t *testing.T) {
+	tests := []struct {
+		A string
+		B string
+	}{
+		{A: "1.0.a", B: "1.0.b"},
+		{A: "1.0.alpha", B: "1.0.beta"},
+		{A: "1.0.beta.2", B: "1.0.rc.1"},
+		{A: "1.0.B", B: "1.0.a"},
+	}
+
+	for _, test := range tests {
+		a, b := MustNew(test.A), MustNew(test.B)
+
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Error("expected", test.A, "to be older than", test.B)
+		}
+	}
+}
+
 // splitSegments splits the segments into integer and alphanumeric arrays.
 func Test_SplitSegments(t *testing.T) {
 	for _, test := range versionTests {
@@ -352,6 +397,45 @@ func Test_Bump(t *testing.T) {
 		t.Fail()
 		return
 	}
+
+	for input, expected := range map[string]string{"1": "2", "1.0": "2", "1:5.3.1": "1:5.4"} {
+		version, err = New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		result, err = version.Bump()
+		if err != nil {
+			t.Error("expected no error but received", err)
+			t.Fail()
+			return
+		}
+
+		if result.Version() != expected {
+			t.Error("expected Bump() of", input, "to be", expected, "but was", result.Version())
+			t.Fail()
+			return
+		}
+	}
+
+	// "a.b" is rejected by New, so build it directly to exercise the
+	// no-numeric-segments path.
+	version = &Version{version: "a.b"}
+
+	result, err = version.Bump()
+	if err == nil {
+		t.Error("expected Bump() of 'a.b' to return an error")
+		t.Fail()
+		return
+	}
+
+	if result != nil {
+		t.Error("expected result to be nil but was", result.Version())
+		t.Fail()
+		return
+	}
 }
 
 // IsPrerelease returns whether the Version is prerelease.
@@ -420,6 +504,13 @@ func Test_Release(t *testing.T) {
 		t.Fail()
 		return
 	}
+
+	v2 = New2("1:1.2.1-2").Release()
+	if v2.Version() != "1:1.2.1" {
+		t.Error("expected v2.Version() to be '1:1.2.1' but was", v2.Version())
+		t.Fail()
+		return
+	}
 }
 
 // A Version is only Eql() to another version if it's specified to the
@@ -488,6 +579,31 @@ func Test_ApproximateRecommendation(t *testing.T) {
 	}
 }
 
+// ApproximateRecommendationAt keeps the requested number of segments.
+func Test_ApproximateRecommendationAt(t *testing.T) {
+	tests := []struct {
+		Version  string
+		Segments int
+		Expected string
+	}{
+		{Version: "1.3.5.7", Segments: 1, Expected: "~> 1"},
+		{Version: "1.3.5.7", Segments: 2, Expected: "~> 1.3"},
+		{Version: "1.3.5.7", Segments: 3, Expected: "~> 1.3.5"},
+		{Version: "1.3.1-4", Segments: 3, Expected: "~> 1.3.1.a"},
+		{Version: "1.3.1-4", Segments: 1, Expected: "~> 1.a"},
+		{Version: "2", Segments: 3, Expected: "~> 2.0.0"},
+		{Version: "2.5", Segments: 0, Expected: "~> 2"},
+		{Version: "1:1.3.5", Segments: 2, Expected: "~> 1:1.3"},
+	}
+
+	for _, test := range tests {
+		result := MustNew(test.Version).ApproximateRecommendationAt(test.Segments)
+		if result != test.Expected {
+			t.Error("expected ApproximateRecommendationAt(", test.Segments, ") of", test.Version, "to be", test.Expected, "but was", result)
+		}
+	}
+}
+
 // reverseSlice sorts the slice s in reverse order.
 func Test_ReverseSlice(t *testing.T) {
 	slice := []string{"a", "b", "c"}
@@ -564,3 +680,1375 @@ func Test_ExtractKind(t *testing.T) {
 		return
 	}
 }
+
+// IsReleaseBoundary is true for ".0" releases only.
+func Test_IsReleaseBoundary(t *testing.T) {
+	tests := map[string]bool{
+		"2":         true,
+		"2.0":       true,
+		"2.0.0":     true,
+		"2.3.0.0":   true,
+		"2.0.1":     false,
+		"2.0.0.1":   false,
+		"2.0.0.pre": false,
+		"0":         true,
+	}
+
+	for input, expected := range tests {
+		if MustNew(input).IsReleaseBoundary() != expected {
+			t.Error("expected IsReleaseBoundary() of", input, "to be", expected)
+		}
+	}
+}
+
+// Original returns the input to New verbatim.
+func Test_Original(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Original string
+		Version  string
+	}{
+		{Input: "1.5-3", Original: "1.5-3", Version: "1.5.pre.3"},
+		{Input: " v1.02 ", Original: " v1.02 ", Version: "1.2"},
+		{Input: "1:2.0+build", Original: "1:2.0+build", Version: "1:2.0+build"},
+	}
+
+	for _, test := range tests {
+		v := MustNew(test.Input)
+
+		if v.Original() != test.Original {
+			t.Error("expected Original() of", test.Input, "to be", test.Original, "but was", v.Original())
+		}
+
+		if v.Version() != test.Version {
+			t.Error("expected Version() of", test.Input, "to be", test.Version, "but was", v.Version())
+		}
+	}
+}
+
+// ToMap returns the version components by name.
+func Test_ToMap(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"1.2.3":                  {"major": 1, "minor": 2, "patch": 3, "prerelease": "", "original": "1.2.3"},
+		"1.2.3-4":                {"major": 1, "minor": 2, "patch": 3, "prerelease": "pre.4", "original": "1.2.3-4"},
+		"2":                      {"major": 2, "minor": 0, "patch": 0, "prerelease": "", "original": "2"},
+		"1.99999999999999999999": {"major": 1, "minor": "99999999999999999999", "patch": 0, "prerelease": "", "original": "1.99999999999999999999"},
+	}
+
+	for input, expected := range tests {
+		result := MustNew(input).ToMap()
+		if !reflect.DeepEqual(result, expected) {
+			t.Error("expected ToMap() of", input, "to be", expected, "but was", result)
+		}
+	}
+}
+
+// ToGoSemver converts to golang.org/x/mod/semver form where possible.
+func Test_ToGoSemver(t *testing.T) {
+	tests := []struct {
+		Version     string
+		Expected    string
+		Convertible bool
+	}{
+		{Version: "1.2.3", Expected: "v1.2.3", Convertible: true},
+		{Version: "v1.2", Expected: "v1.2.0", Convertible: true},
+		{Version: "1.0.0.rc1", Expected: "v1.0.0-rc.1", Convertible: true},
+		{Version: "1.0.0-rc1", Expected: "v1.0.0-pre.rc.1", Convertible: true},
+		{Version: "1.0.0.beta.2", Expected: "v1.0.0-beta.2", Convertible: true},
+		{Version: "1.5-3", Expected: "v1.5.0-pre.3", Convertible: true},
+		{Version: "2.0.0+build.5", Expected: "v2.0.0+build.5", Convertible: true},
+		{Version: "1.2.3.4", Convertible: false},
+		{Version: "1:1.2.3", Convertible: false},
+		{Version: "1.0.0.a.01", Convertible: false},
+	}
+
+	for _, test := range tests {
+		result, ok := MustNew(test.Version).ToGoSemver()
+		if ok != test.Convertible || result != test.Expected {
+			t.Error("expected ToGoSemver() of", test.Version, "to be", test.Expected, test.Convertible, "but was", result, ok)
+		}
+	}
+}
+
+// FromSegments builds a version from numeric and prerelease segments.
+func Test_FromSegments(t *testing.T) {
+	tests := []struct {
+		Numeric    []int
+		Prerelease []string
+		Expected   string
+	}{
+		{Numeric: []int{1, 2, 3}, Expected: "1.2.3"},
+		{Numeric: []int{1, 2, 3}, Prerelease: []string{"rc", "1"}, Expected: "1.2.3.rc.1"},
+		{Numeric: []int{2}, Prerelease: []string{"beta2"}, Expected: "2.beta.2"},
+	}
+
+	for _, test := range tests {
+		v, err := FromSegments(test.Numeric, test.Prerelease...)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if v.Version() != test.Expected {
+			t.Error("expected FromSegments(", test.Numeric, test.Prerelease, ") to be", test.Expected, "but was", v.Version())
+		}
+	}
+
+	if v, err := FromSegments([]int{1, 2, 3}, "rc", "1"); err != nil || !v.IsPrerelease() {
+		t.Error("expected a version with prerelease segments to be a prerelease")
+	}
+
+	for _, numeric := range [][]int{nil, {}, {1, -2}} {
+		if _, err := FromSegments(numeric); err == nil {
+			t.Error("expected FromSegments(", numeric, ") to return an error")
+		}
+	}
+
+	if _, err := FromSegments([]int{1}, "r c"); !errors.Is(err, ErrMalformedVersion) {
+		t.Error("expected an invalid prerelease segment to return ErrMalformedVersion but got", err)
+	}
+	invalid := []struct {
+		Prerelease []string
+		Position   int
+	}{
+		{Prerelease: []string{""}, Position: 4},
+		{Prerelease: []string{"rc", ""}, Position: 7},
+		{Prerelease: []string{"3"}, Position: 4},
+		{Prerelease: []string{"a+b"}, Position: 4},
+		{Prerelease: []string{"rc-1"}, Position: 4},
+		{Prerelease: []string{"rc", "1.2"}, Position: 7},
+	}
+
+	for _, test := range invalid {
+		_, err := FromSegments([]int{1, 2}, test.Prerelease...)
+
+		var malformed *ErrMalformed
+		if !errors.As(err, &malformed) || malformed.Position != test.Position {
+			t.Error("expected FromSegments with prerelease", test.Prerelease, "to fail at position", test.Position, "but got", err)
+		}
+	}
+}
+
+// Major, Minor and Patch return the leading numeric segments.
+func Test_MajorMinorPatch(t *testing.T) {
+	tests := []struct {
+		Version string
+		Major   int
+		Minor   int
+		Patch   int
+	}{
+		{Version: "1", Major: 1, Minor: 0, Patch: 0},
+		{Version: "1.2", Major: 1, Minor: 2, Patch: 0},
+		{Version: "1.2.3.4", Major: 1, Minor: 2, Patch: 3},
+		{Version: "1.5-3", Major: 1, Minor: 5, Patch: 0},
+		{Version: "2.0.a10", Major: 2, Minor: 0, Patch: 0},
+	}
+
+	for _, test := range tests {
+		v, err := New(test.Version)
+		if err != nil {
+			t.Error("expected", test.Version, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		if v.Major() != test.Major || v.Minor() != test.Minor || v.Patch() != test.Patch {
+			t.Errorf("expected %s to have components %d.%d.%d but got %d.%d.%d",
+				test.Version, test.Major, test.Minor, test.Patch, v.Major(), v.Minor(), v.Patch())
+		}
+	}
+
+	if major := MustNew("99999999999999999999").Major(); major != math.MaxInt {
+		t.Error("expected a segment too large for an int to be clamped to math.MaxInt but got", major)
+	}
+}
+
+// Cmp parses two version strings and returns their Order.
+func Test_Cmp(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Expected Order
+	}{
+		{A: "1.0", B: "1.1", Expected: Less},
+		{A: "1.0", B: "1", Expected: Equal},
+		{A: "1.12", B: "1.2", Expected: Greater},
+		{A: "1.0.a", B: "1.0", Expected: Less},
+	}
+
+	for _, test := range tests {
+		result, err := Cmp(test.A, test.B)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			t.Fail()
+			return
+		}
+
+		if result != test.Expected {
+			t.Error("expected Cmp(", test.A, ",", test.B, ") to be", test.Expected, "but was", result)
+		}
+	}
+
+	if _, err := Cmp("1.", "1.0"); err == nil {
+		t.Error("expected Cmp to return an error for a malformed first argument")
+	}
+
+	if _, err := Cmp("1.0", "1.5-"); err == nil {
+		t.Error("expected Cmp to return an error for a malformed second argument")
+	}
+}
+
+// EqualString compares against a version string canonically.
+func Test_EqualString(t *testing.T) {
+	v := MustNew("1.0")
+
+	for input, expected := range map[string]bool{"1": true, "v1.0.0": true, "1.0+build": true, "1.0.1": false, "1.0.a": false, "1:1.0": false} {
+		equal, err := v.EqualString(input)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if equal != expected {
+			t.Error("expected EqualString(", input, ") of 1.0 to be", expected)
+		}
+	}
+
+	if equal, err := v.EqualString("1."); !errors.Is(err, ErrMalformedVersion) || equal {
+		t.Error("expected a malformed comparand to return false and the parse error but got", equal, err)
+	}
+	if equal, err := MustNew("1.a.01").EqualString("1.a.1"); err != nil || !equal {
+		t.Error("expected 1.a.01 to equal 1.a.1 but got", equal, err)
+	}
+}
+
+// SafeCompare returns an error instead of panicking on nil versions.
+func Test_SafeCompare(t *testing.T) {
+	v := MustNew("1.2")
+
+	cmp, err := v.SafeCompare(MustNew("1.10"))
+	if err != nil || cmp != -1 {
+		t.Error("expected SafeCompare to return -1 and no error but got", cmp, err)
+	}
+
+	if _, err := v.SafeCompare(nil); !errors.Is(err, ErrNilVersion) {
+		t.Error("expected a nil argument to return ErrNilVersion but got", err)
+	}
+
+	var nilVersion *Version
+	if _, err := nilVersion.SafeCompare(v); !errors.Is(err, ErrNilVersion) {
+		t.Error("expected a nil receiver to return ErrNilVersion but got", err)
+	}
+}
+
+// ComparePrecise reports differences in precision alongside Compare.
+func Test_ComparePrecise(t *testing.T) {
+	tests := []struct {
+		A             string
+		B             string
+		Compare       int
+		SamePrecision bool
+	}{
+		{A: "1.0", B: "1", Compare: 0, SamePrecision: false},
+		{A: "1.2.3", B: "1.2.3", Compare: 0, SamePrecision: true},
+		{A: "1.2.3", B: "1.2.4", Compare: -1, SamePrecision: true},
+		{A: "1.3", B: "1.2.9", Compare: 1, SamePrecision: false},
+	}
+
+	for _, test := range tests {
+		cmp, samePrecision := MustNew(test.A).ComparePrecise(MustNew(test.B))
+		if cmp != test.Compare || samePrecision != test.SamePrecision {
+			t.Error("expected ComparePrecise of", test.A, "and", test.B, "to be", test.Compare, test.SamePrecision, "but was", cmp, samePrecision)
+		}
+	}
+}
+
+// CompareFold ignores the case of prerelease labels, unlike Compare.
+func Test_CompareFold(t *testing.T) {
+	tests := []struct {
+		A           string
+		B           string
+		Compare     int
+		CompareFold int
+	}{
+		{A: "1.0.RC1", B: "1.0.rc1", Compare: -1, CompareFold: 0},
+		{A: "1.0.rc1", B: "1.0.RC1", Compare: 1, CompareFold: 0},
+		{A: "1.0.Beta", B: "1.0.alpha", Compare: -1, CompareFold: 1},
+		{A: "1.0.RC2", B: "1.0.rc1", Compare: -1, CompareFold: 1},
+		{A: "1.0.RC1", B: "1.0", Compare: -1, CompareFold: -1},
+		{A: "1.2", B: "1.10", Compare: -1, CompareFold: -1},
+	}
+
+	for _, test := range tests {
+		a, b := MustNew(test.A), MustNew(test.B)
+
+		if a.Compare(b) != test.Compare {
+			t.Error("expected", test.A, "<=>", test.B, "to be", test.Compare, "but was", a.Compare(b))
+		}
+
+		if a.CompareFold(b) != test.CompareFold {
+			t.Error("expected CompareFold of", test.A, "and", test.B, "to be", test.CompareFold, "but was", a.CompareFold(b))
+		}
+	}
+}
+
+// CompareOrdering maps each Compare result to its named Ordering.
+func Test_CompareOrdering(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Numeric  int
+		Expected Ordering
+	}{
+		{A: "1.0", B: "1.1", Numeric: -1, Expected: Older},
+		{A: "1.0", B: "1", Numeric: 0, Expected: Same},
+		{A: "1.12", B: "1.2", Numeric: 1, Expected: Newer},
+	}
+
+	for _, test := range tests {
+		a, b := MustNew(test.A), MustNew(test.B)
+
+		if a.Compare(b) != test.Numeric {
+			t.Error("expected", test.A, "<=>", test.B, "to be", test.Numeric, "but was", a.Compare(b))
+		}
+
+		if a.CompareOrdering(b) != test.Expected {
+			t.Error("expected CompareOrdering of", test.A, "and", test.B, "to be", test.Expected, "but was", a.CompareOrdering(b))
+		}
+	}
+
+	if Ordering(2).String() != "Ordering(2)" {
+		t.Error("expected an unknown Ordering to format as Ordering(2) but got", Ordering(2).String())
+	}
+}
+
+// Prerelease returns the prerelease portion of the version.
+func Test_Prerelease(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":   "",
+		"1.0.b":   "b",
+		"1.5-3":   "pre.3",
+		"1.0.a10": "a.10",
+	}
+
+	for input, expected := range tests {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		if v.Prerelease() != expected {
+			t.Error("expected Prerelease() of", input, "to be", expected, "but was", v.Prerelease())
+		}
+	}
+}
+
+// NumSegments counts the split segments of the version.
+func Test_NumSegments(t *testing.T) {
+	tests := map[string]int{
+		"1":       1,
+		"1.2":     2,
+		"1.2.3":   3,
+		"1.0":     2,
+		"1.5-3":   4,
+		"1.0.a10": 4,
+	}
+
+	for input, expected := range tests {
+		if MustNew(input).NumSegments() != expected {
+			t.Error("expected NumSegments() of", input, "to be", expected, "but was", MustNew(input).NumSegments())
+		}
+	}
+
+	var zero Version
+	if zero.NumSegments() != len(zero.segments()) {
+		t.Error("expected NumSegments() of the zero Version to match its segments")
+	}
+}
+
+// Segments, CanonicalSegments and SplitSegments match their private counterparts.
+func Test_ExportedSegments(t *testing.T) {
+	for _, test := range versionTests {
+		if !test.ExpectedResponse {
+			continue
+		}
+
+		v, err := New(test.Version)
+		if err != nil {
+			t.Error("testing bug: version should be valid for test:", test.Version)
+			t.Fail()
+			return
+		}
+
+		if !strArraysEqual(v.Segments(), test.ExpectedSegments) {
+			t.Error("expected Segments() to be", test.ExpectedSegments, "but was", v.Segments())
+		}
+
+		if !strArraysEqual(v.CanonicalSegments(), test.ExpectedCanonicalSegments) {
+			t.Error("expected CanonicalSegments() to be", test.ExpectedCanonicalSegments, "but was", v.CanonicalSegments())
+		}
+
+		numeric, alpha := v.SplitSegments()
+
+		if !strArraysEqual(numeric, test.ExpectedNumericSegments) {
+			t.Error("expected numeric segments to be", test.ExpectedNumericSegments, "but was", numeric)
+		}
+
+		if !strArraysEqual(alpha, test.ExpectedStringSegments) {
+			t.Error("expected string segments to be", test.ExpectedStringSegments, "but was", alpha)
+		}
+	}
+}
+
+// New strips a single leading "v" directly followed by a digit.
+func Test_NewVersionPrefix(t *testing.T) {
+	for input, expected := range map[string]string{"v1.2.3": "1.2.3", "V2.0": "2.0"} {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		if v.Version() != expected {
+			t.Error("expected Version() of", input, "to be", expected, "but was", v.Version())
+		}
+	}
+
+	for _, input := range []string{"version", "v.1", "vv1.2", "v"} {
+		if v, err := New(input); err == nil {
+			t.Error("expected", input, "to be invalid but got", v.Version())
+		}
+	}
+}
+
+// MustNew returns a *Version or panics on malformed input.
+func Test_MustNew(t *testing.T) {
+	v := MustNew("1.2.0")
+	if v == nil || v.Version() != "1.2.0" {
+		t.Error("expected MustNew to return a Version of '1.2.0'")
+		t.Fail()
+		return
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustNew to panic for '1.'")
+		}
+	}()
+
+	MustNew("1.")
+}
+
+// compareNumeric compares numeric segments of arbitrary size.
+func Test_CompareLargeSegments(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Expected int
+	}{
+		{A: "20231015123456789", B: "20231015123456790", Expected: -1},
+		{A: "1.99999999999999999999", B: "1.100000000000000000000", Expected: -1},
+		{A: "18446744073709551616", B: "9223372036854775807", Expected: 1},
+		{A: "1.18446744073709551616", B: "1.18446744073709551616.0", Expected: 0},
+	}
+
+	for _, test := range tests {
+		a, err := New(test.A)
+		if err != nil {
+			t.Error("expected", test.A, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		b, err := New(test.B)
+		if err != nil {
+			t.Error("expected", test.B, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		if a.Compare(b) != test.Expected {
+			t.Error("expected", test.A, "compared to", test.B, "to be", test.Expected, "but was", a.Compare(b))
+		}
+
+		if b.Compare(a) != -test.Expected {
+			t.Error("expected", test.B, "compared to", test.A, "to be", -test.Expected, "but was", b.Compare(a))
+		}
+	}
+}
+
+// Coerce extracts a version from messy input.
+func Test_Coerce(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3-beta+build": "1.2.3.pre.beta",
+		"Release 4":        "4",
+		"  2.0  ":          "2.0",
+		"app-v3.1.4 final": "3.1.4",
+	}
+
+	for input, expected := range tests {
+		v, err := Coerce(input)
+		if err != nil {
+			t.Error("expected Coerce(", input, ") not to return an error but got", err)
+			continue
+		}
+
+		if v.Version() != expected {
+			t.Error("expected Coerce(", input, ") to be", expected, "but was", v.Version())
+		}
+	}
+
+	if v, err := Coerce("no numbers here"); err == nil || v != nil {
+		t.Error("expected Coerce to return an error when no version is present")
+	}
+}
+
+// Build metadata is preserved on output but ignored when comparing.
+func Test_BuildMetadata(t *testing.T) {
+	v, err := New("1.0.0+20130313144700")
+	if err != nil {
+		t.Error("expected '1.0.0+20130313144700' to be a valid version but got error", err)
+		t.Fail()
+		return
+	}
+
+	if v.Version() != "1.0.0+20130313144700" {
+		t.Error("expected Version() to preserve build metadata but was", v.Version())
+	}
+
+	if v.Build() != "20130313144700" {
+		t.Error("expected Build() to be '20130313144700' but was", v.Build())
+	}
+
+	if v.IsPrerelease() {
+		t.Error("expected build metadata not to make a version prerelease")
+	}
+
+	if !strArraysEqual(v.Segments(), []string{"1", "0", "0"}) {
+		t.Error("expected build metadata to be excluded from Segments() but got", v.Segments())
+	}
+
+	other, err := New("1.0.0+exp.sha.5114f85")
+	if err != nil {
+		t.Error("expected '1.0.0+exp.sha.5114f85' to be a valid version but got error", err)
+		t.Fail()
+		return
+	}
+
+	if v.Compare(other) != 0 {
+		t.Error("expected versions differing only in build metadata to compare equal")
+	}
+
+	pre, err := New("1.0.0-rc-1+build.1")
+	if err != nil {
+		t.Error("expected '1.0.0-rc-1+build.1' to be a valid version but got error", err)
+		t.Fail()
+		return
+	}
+
+	if pre.Version() != "1.0.0.pre.rc.pre.1+build.1" || pre.Compare(v) != -1 {
+		t.Error("expected build metadata to be kept separate from the prerelease part but got", pre.Version())
+	}
+
+	for _, input := range []string{"1.0+", "1.0+build+more", "1.0+bu_ild"} {
+		if _, err := New(input); err == nil {
+			t.Error("expected", input, "to be invalid")
+		}
+	}
+}
+
+// DiffLevel reports the highest-order level that changed.
+func Test_DiffLevel(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Expected string
+	}{
+		{A: "1.0.0", B: "2.0.0", Expected: "major"},
+		{A: "1.0.0", B: "1.1.0", Expected: "minor"},
+		{A: "1.0.0", B: "1.0.1", Expected: "patch"},
+		{A: "1.0.0.1", B: "1.0.0.2", Expected: "patch"},
+		{A: "1.0.0", B: "1.0.0-1", Expected: "prerelease"},
+		{A: "1.0.0.a", B: "1.0.0.b", Expected: "prerelease"},
+		{A: "1.0.0", B: "1", Expected: "none"},
+		{A: "1.0.0+a", B: "1.0.0+b", Expected: "none"},
+		{A: "1:1.0", B: "1.0", Expected: "major"},
+		{A: "1:1.0", B: "2:1.0.1", Expected: "major"},
+	}
+
+	for _, test := range tests {
+		a, b := New2(test.A), New2(test.B)
+		if a == nil || b == nil {
+			t.Error("testing bug: versions should be valid:", test.A, test.B)
+			t.Fail()
+			return
+		}
+
+		if a.DiffLevel(b) != test.Expected {
+			t.Error("expected DiffLevel from", test.A, "to", test.B, "to be", test.Expected, "but was", a.DiffLevel(b))
+		}
+
+		if b.DiffLevel(a) != test.Expected {
+			t.Error("expected DiffLevel from", test.B, "to", test.A, "to be", test.Expected, "but was", b.DiffLevel(a))
+		}
+	}
+}
+
+// Between checks that a version lies in [low, high).
+func Test_Between(t *testing.T) {
+	low, high := New2("1.2"), New2("2.0")
+
+	tests := []struct {
+		Version  string
+		Low      *Version
+		High     *Version
+		Expected bool
+	}{
+		{Version: "1.2", Low: low, High: high, Expected: true},
+		{Version: "1.2.0", Low: low, High: high, Expected: true},
+		{Version: "1.9.9", Low: low, High: high, Expected: true},
+		{Version: "2.0", Low: low, High: high, Expected: false},
+		{Version: "2.0.a", Low: low, High: high, Expected: true},
+		{Version: "1.1.9", Low: low, High: high, Expected: false},
+		{Version: "0.1", Low: nil, High: high, Expected: true},
+		{Version: "9.0", Low: low, High: nil, Expected: true},
+		{Version: "9.0", Low: nil, High: nil, Expected: true},
+	}
+
+	for _, test := range tests {
+		v := New2(test.Version)
+		if v.Between(test.Low, test.High) != test.Expected {
+			t.Error("expected", test.Version, "Between to be", test.Expected)
+		}
+	}
+}
+
+// Sorting a large list of versions benefits from the cached segments.
+// The uncached case recomputes them on every comparison, as Compare did
+// before segments were cached.
+func Benchmark_Sort(b *testing.B) {
+	cached := make([]*Version, 10000)
+	uncached := make([]*Version, len(cached))
+	for i := range cached {
+		cached[i] = New2(fmt.Sprintf("%d.%d.%d", (i*7919)%13, (i*104729)%97, i%31))
+		uncached[i] = &Version{version: cached[i].version, original: cached[i].original}
+	}
+
+	sortBenchmark := func(versions []*Version) func(*testing.B) {
+		return func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				sorted := make([]*Version, len(versions))
+				copy(sorted, versions)
+
+				sort.Slice(sorted, func(i, j int) bool {
+					return sorted[i].Compare(sorted[j]) < 0
+				})
+			}
+		}
+	}
+
+	b.Run("cached", sortBenchmark(cached))
+	b.Run("uncached", sortBenchmark(uncached))
+}
+
+// Trailing zeros are trimmed in linear time, so a long run of them
+// doesn't make New slow.
+func Test_NewTrailingZeros(t *testing.T) {
+	v, err := New("1" + strings.Repeat(".0", 20000))
+	if err != nil {
+		t.Error("expected a long run of zeros to be a valid version but got error", err)
+		t.Fail()
+		return
+	}
+
+	if v.Canonical() != "1" {
+		t.Error("expected the canonical form to be '1' but was", v.Canonical())
+	}
+
+	if c := New2("1.0.pre.0.0").CanonicalSegments(); len(c) != 2 || c[0] != "1" || c[1] != "pre" {
+		t.Error("expected the canonical segments of 1.0.pre.0.0 to be [1 pre] but were", c)
+	}
+}
+
+// NextPrerelease increments or starts the prerelease number.
+func Test_NextPrerelease(t *testing.T) {
+	tests := map[string]string{
+		"1.2.0.pre.1": "1.2.0.pre.2",
+		"1.2.0-9":     "1.2.0.pre.10",
+		"1.2.0.beta":  "1.2.0.beta.1",
+		"1.2.0":       "1.2.0.pre.1",
+		"1.0.a10":     "1.0.a.11",
+		"1:1.2.0.b.1": "1:1.2.0.b.2",
+	}
+
+	for input, expected := range tests {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			t.Fail()
+			return
+		}
+
+		next, err := v.NextPrerelease()
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if next.Version() != expected {
+			t.Error("expected NextPrerelease() of", input, "to be", expected, "but was", next.Version())
+		}
+
+		if next.Release().Compare(v.Release()) != 0 {
+			t.Error("expected NextPrerelease() of", input, "to keep the release portion")
+		}
+	}
+}
+
+// Canonical returns the version without trailing zero segments.
+func Test_Canonical(t *testing.T) {
+	tests := map[string]string{
+		"1.2.0":      "1.2",
+		"1.0.0":      "1",
+		"1.0":        "1",
+		"1.2.3":      "1.2.3",
+		"1.0.0.a.0":  "1.a",
+		"2.3-0-0":    "2.3.pre.0.pre",
+		"1.2.0+b.10": "1.2",
+		"0":          "0",
+		"0.0":        "0",
+		"0.a":        "0.a",
+		"0.0.pre.1":  "0.pre.1",
+		"1:0.0-1":    "1:0.pre.1",
+	}
+
+	for input, expected := range tests {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			continue
+		}
+
+		if v.Canonical() != expected {
+			t.Error("expected Canonical() of", input, "to be", expected, "but was", v.Canonical())
+		}
+
+		if parsed, err := New(v.Canonical()); err != nil || parsed.Compare(v) != 0 {
+			t.Error("expected Canonical() of", input, "to round-trip through New but got", parsed, err)
+		}
+	}
+}
+
+// Clone returns an independent copy of the version.
+func Test_Clone(t *testing.T) {
+	v := New2("1.2.3-4+build")
+
+	clone := v.Clone()
+	if clone == v {
+		t.Error("expected Clone to return a distinct pointer")
+	}
+
+	if !clone.Eql(v) || clone.Version() != v.Version() || clone.Compare(v) != 0 {
+		t.Error("expected clone to equal", v.Version(), "but was", clone.Version())
+	}
+
+	var nilVersion *Version
+	if nilVersion.Clone() != nil {
+		t.Error("expected Clone of a nil *Version to be nil")
+	}
+}
+
+// IncrementAt bumps an arbitrary numeric segment.
+func Test_IncrementAt(t *testing.T) {
+	tests := []struct {
+		Version  string
+		Index    int
+		Expected string
+	}{
+		{Version: "1.2.3.4", Index: 1, Expected: "1.3.0.0"},
+		{Version: "1.2.3.4", Index: 3, Expected: "1.2.3.5"},
+		{Version: "1.2.3.4", Index: 0, Expected: "2.0.0.0"},
+		{Version: "1.2.3-4", Index: 2, Expected: "1.2.4"},
+		{Version: "1:1.2.3", Index: 1, Expected: "1:1.3.0"},
+	}
+
+	for _, test := range tests {
+		result, err := New2(test.Version).IncrementAt(test.Index)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if result.Version() != test.Expected {
+			t.Error("expected IncrementAt(", test.Index, ") of", test.Version, "to be", test.Expected, "but was", result.Version())
+		}
+	}
+
+	for _, index := range []int{-1, 4, 10} {
+		if _, err := New2("1.2.3.4").IncrementAt(index); err == nil {
+			t.Error("expected IncrementAt(", index, ") to return an error for an out of range index")
+		}
+	}
+
+	for _, index := range []int{2, 3} {
+		if _, err := New2("1.0.pre.3").IncrementAt(index); err == nil {
+			t.Error("expected IncrementAt(", index, ") to return an error for a prerelease segment")
+		}
+	}
+}
+
+// IsOlderThan and IsNewerThan wrap Compare.
+func Test_IsOlderThanIsNewerThan(t *testing.T) {
+	tests := []struct {
+		Older string
+		Newer string
+	}{
+		{Older: "1.0", Newer: "1.1"},
+		{Older: "1.0.a", Newer: "1.0"},
+		{Older: "2.0.0-1", Newer: "2.0.0"},
+		{Older: "1.9", Newer: "2.0.b1"},
+	}
+
+	for _, test := range tests {
+		older, newer := New2(test.Older), New2(test.Newer)
+
+		if !older.IsOlderThan(newer) || older.IsNewerThan(newer) {
+			t.Error("expected", test.Older, "to be older than", test.Newer)
+		}
+
+		if !newer.IsNewerThan(older) || newer.IsOlderThan(older) {
+			t.Error("expected", test.Newer, "to be newer than", test.Older)
+		}
+	}
+
+	a, b := New2("1.0"), New2("1.0.0")
+	if a.IsOlderThan(b) || a.IsNewerThan(b) {
+		t.Error("expected equal versions to be neither older nor newer")
+	}
+}
+
+// IsImmediateSuccessorOf and IsImmediateSuccessorAt detect adjacent releases.
+func Test_IsImmediateSuccessor(t *testing.T) {
+	tests := []struct {
+		From  string
+		To    string
+		Any   bool
+		Patch bool
+		Minor bool
+	}{
+		{From: "1.2.3", To: "1.2.4", Any: true, Patch: true},
+		{From: "1.2.3", To: "1.3.0", Any: true, Minor: true},
+		{From: "1.2.3", To: "1.3", Any: true, Minor: true},
+		{From: "1.2.3", To: "2", Any: true},
+		{From: "1.2.3", To: "1.2.5"},
+		{From: "1.2.3", To: "1.3.1"},
+		{From: "1.2.3", To: "1.2.4.a"},
+		{From: "1.2.3", To: "1.2.3"},
+		{From: "1.2.3.b.1", To: "1.2.4", Any: true, Patch: true},
+		{From: "1:1.2.3", To: "1:1.2.4", Any: true, Patch: true},
+		{From: "1:1.2.3", To: "1.2.4"},
+	}
+
+	for _, test := range tests {
+		from, to := MustNew(test.From), MustNew(test.To)
+
+		if to.IsImmediateSuccessorOf(from) != test.Any {
+			t.Error("expected", test.To, "IsImmediateSuccessorOf", test.From, "to be", test.Any)
+		}
+
+		if to.IsImmediateSuccessorAt(from, 2) != test.Patch {
+			t.Error("expected", test.To, "IsImmediateSuccessorAt patch of", test.From, "to be", test.Patch)
+		}
+
+		if to.IsImmediateSuccessorAt(from, 1) != test.Minor {
+			t.Error("expected", test.To, "IsImmediateSuccessorAt minor of", test.From, "to be", test.Minor)
+		}
+	}
+
+	if MustNew("1.3").IsImmediateSuccessorAt(MustNew("1.2"), 2) {
+		t.Error("expected IsImmediateSuccessorAt to be false for a missing segment")
+	}
+}
+
+// Truncate keeps the first n segments.
+func Test_Truncate(t *testing.T) {
+	tests := []struct {
+		Version  string
+		N        int
+		Expected string
+	}{
+		{Version: "1.2.3.4", N: 2, Expected: "1.2"},
+		{Version: "1.2.3.4", N: 1, Expected: "1"},
+		{Version: "1.2.3.4", N: 4, Expected: "1.2.3.4"},
+		{Version: "1.0-3", N: 2, Expected: "1.0"},
+		{Version: "1.0-3", N: 3, Expected: "1.0.pre"},
+		{Version: "1.0.a10", N: 4, Expected: "1.0.a.10"},
+		{Version: "2:1.2.3", N: 2, Expected: "2:1.2"},
+	}
+
+	for _, test := range tests {
+		result, err := New2(test.Version).Truncate(test.N)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if result.Version() != test.Expected {
+			t.Error("expected Truncate(", test.N, ") of", test.Version, "to be", test.Expected, "but was", result.Version())
+		}
+	}
+
+	for _, n := range []int{0, -1, 5} {
+		if _, err := New2("1.2.3.4").Truncate(n); err == nil {
+			t.Error("expected Truncate(", n, ") to return an error")
+		}
+	}
+}
+
+// CompareAscending sorts versions from oldest to newest with slices.SortFunc.
+func Test_CompareAscending(t *testing.T) {
+	var versions []*Version
+	for _, input := range []string{"1.0", "0.9", "1.0.a.2", "1.0.b1", "1.10", "1.2"} {
+		versions = append(versions, New2(input))
+	}
+
+	slices.SortFunc(versions, CompareAscending)
+
+	expected := []string{"0.9", "1.0.a.2", "1.0.b.1", "1.0", "1.2", "1.10"}
+	for i, v := range versions {
+		if v.Version() != expected[i] {
+			t.Error("expected version", i, "to be", expected[i], "but was", v.Version())
+		}
+	}
+}
+
+// IsZero reports whether the version is equal to "0".
+func Test_IsZero(t *testing.T) {
+	for input, expected := range map[string]bool{"0": true, "0.0.0": true, "": true, "  ": true, "0.1": false, "1": false, "0.0.a": false, "1:0": false} {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			continue
+		}
+
+		if v.IsZero() != expected {
+			t.Error("expected IsZero() of '", input, "' to be", expected)
+		}
+	}
+
+	if New2("").Version() != "0" {
+		t.Error("expected a blank version to be '0' but was", New2("").Version())
+	}
+}
+
+// New splits segments mixing letters and digits.
+func Test_NewMixedSegments(t *testing.T) {
+	tests := map[string]string{
+		"1.0.a10":   "1.0.a.10",
+		"1.0.rc1b":  "1.0.rc.1.b",
+		"2.3-beta2": "2.3.pre.beta.2",
+		"1.2.3":     "1.2.3",
+		"1.02a":     "1.2.a",
+	}
+
+	for input, expected := range tests {
+		if MustNew(input).Version() != expected {
+			t.Error("expected Version() of", input, "to be", expected, "but was", MustNew(input).Version())
+		}
+	}
+
+	if MustNew("1.0.a10").Compare(MustNew("1.0.a9")) != 1 {
+		t.Error("expected 1.0.a10 to be greater than 1.0.a9")
+	}
+
+	if !MustNew("1.0.a10").Eql(MustNew("1.0.a.10")) {
+		t.Error("expected 1.0.a10 to be Eql to 1.0.a.10")
+	}
+}
+
+// New removes leading zeros from numeric release segments.
+func Test_NewLeadingZeros(t *testing.T) {
+	tests := map[string]string{
+		"1.02":       "1.2",
+		"01.2":       "1.2",
+		"1.0.007":    "1.0.7",
+		"0":          "0",
+		"00.000":     "0.0",
+		"1.02-03":    "1.2.pre.03",
+		"1.02.a007":  "1.2.a.007",
+		"1.02+build": "1.2+build",
+	}
+
+	for input, expected := range tests {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			continue
+		}
+
+		if v.Version() != expected {
+			t.Error("expected Version() of", input, "to be", expected, "but was", v.Version())
+		}
+	}
+
+	if !New2("1.02").Eql(New2("1.2")) {
+		t.Error("expected '1.02' to be Eql to '1.2'")
+	}
+}
+
+// Hash is shared by versions that compare equal.
+func Test_Hash(t *testing.T) {
+	set := map[string]*Version{}
+
+	for _, input := range []string{"1.0", "1", "1.0.0", "1.0.0+build"} {
+		v := New2(input)
+		set[v.Hash()] = v
+	}
+
+	if len(set) != 1 {
+		t.Error("expected a single entry but got", len(set))
+	}
+
+	if New2("1.0.1").Hash() == New2("1.0").Hash() {
+		t.Error("expected different versions to have different hashes")
+	}
+
+	if New2("1.0.a").Hash() == New2("1.0").Hash() {
+		t.Error("expected a prerelease to have a different hash from its release")
+	}
+
+	if New2("1.a.01").Hash() != New2("1.a.1").Hash() {
+		t.Error("expected leading zeros in prerelease segments not to change the hash")
+	}
+}
+
+// Normalize collapses equivalent versions to the same text.
+func Test_Normalize(t *testing.T) {
+	classes := [][]string{
+		{"1.5-3", "1.5.pre.3", "v1.5-3", "1.05.pre.3", "1.5.0-3"},
+		{"1", "1.0", "1.0.0", "01.0+build"},
+		{"2.3-0-0", "2.3.pre.0.pre"},
+		{"1.a.1", "1.a.01", "1.0.a.001.0"},
+	}
+
+	for _, class := range classes {
+		expected, err := Normalize(class[0])
+		if err != nil {
+			t.Error("expected", class[0], "to normalize but got error", err)
+			continue
+		}
+
+		for _, input := range class[1:] {
+			normalized, err := Normalize(input)
+			if err != nil {
+				t.Error("expected", input, "to normalize but got error", err)
+				continue
+			}
+
+			if normalized != expected {
+				t.Error("expected", input, "to normalize to", expected, "but got", normalized)
+			}
+		}
+	}
+
+	for _, input := range []string{"0.a", "0.0.pre.1"} {
+		normalized, err := Normalize(input)
+		if err != nil {
+			t.Error("expected", input, "to normalize but got error", err)
+			continue
+		}
+
+		if parsed, err := New(normalized); err != nil || parsed.Compare(MustNew(input)) != 0 {
+			t.Error("expected the normalized form", normalized, "of", input, "to round-trip through New but got", err)
+		}
+	}
+
+	if _, err := Normalize("1."); err == nil {
+		t.Error("expected Normalize to return an error for a malformed version")
+	}
+}
+
+// EquivalentForms lists spellings that Compare equal to the input.
+func Test_EquivalentForms(t *testing.T) {
+	tests := map[string][]string{
+		"1.5-3":    {"1.5.pre.3", "1.5.0.pre.3", "1.5-3", "1.5.0-3"},
+		"1.0.0":    {"1", "1.0", "1.0.0"},
+		"1.2.3.4":  {"1.2.3.4"},
+		"0":        {"0", "0.0", "0.0.0"},
+		"2:1.0.a1": {"2:1.a.1", "2:1.0.a.1", "2:1.0.0.a.1"},
+		"2.3-0-0":  {"2.3.pre.0.pre", "2.3.0.pre.0.pre", "2.3-0.pre", "2.3.0-0.pre"},
+		"1.a.01":   {"1.a.1", "1.0.a.1", "1.0.0.a.1"},
+	}
+
+	for input, expected := range tests {
+		forms, err := EquivalentForms(input)
+		if err != nil {
+			t.Error("expected", input, "to be valid but got error", err)
+			continue
+		}
+
+		if !reflect.DeepEqual(forms, expected) {
+			t.Error("expected EquivalentForms of", input, "to be", expected, "but was", forms)
+		}
+
+		for _, form := range forms {
+			if MustNew(form).Compare(MustNew(input)) != 0 {
+				t.Error("expected", form, "to compare equal to", input)
+			}
+		}
+	}
+
+	if _, err := EquivalentForms("1."); err == nil {
+		t.Error("expected EquivalentForms to return an error for a malformed version")
+	}
+}
+
+// An epoch dominates the rest of the version in comparison.
+func Test_Epoch(t *testing.T) {
+	v, err := New("1:1.0")
+	if err != nil {
+		t.Error("expected '1:1.0' to be a valid version but got error", err)
+		t.Fail()
+		return
+	}
+
+	if v.Epoch() != 1 || v.Version() != "1:1.0" || v.Canonical() != "1:1" {
+		t.Error("unexpected epoch version:", v.Epoch(), v.Version(), v.Canonical())
+	}
+
+	old := New2("9.9")
+	if old.Epoch() != 0 {
+		t.Error("expected a version without an epoch to have epoch 0")
+	}
+
+	if v.Compare(old) != 1 || old.Compare(v) != -1 {
+		t.Error("expected 1:1.0 to be newer than 9.9")
+	}
+
+	if New2("2:0.1").Compare(v) != 1 {
+		t.Error("expected 2:0.1 to be newer than 1:1.0")
+	}
+
+	if New2("0:1.0").Compare(New2("1.0")) != 0 || !New2("0:1.0").Eql(New2("1.0")) {
+		t.Error("expected an explicit zero epoch to equal no epoch")
+	}
+
+	if New2("1:1.0").Eql(New2("1.0")) || New2("1:1.0").Hash() == New2("1.0").Hash() {
+		t.Error("expected versions with different epochs not to be equal")
+	}
+
+	for _, input := range []string{":1.0", "1:", "a:1.0", "1:2:3.0", "99999999999999999999:1.0"} {
+		if _, err := New(input); err == nil {
+			t.Error("expected", input, "to be invalid")
+		}
+	}
+}
+
+// IsSemVer distinguishes strict SemVer from RubyGems-only forms.
+func Test_IsSemVer(t *testing.T) {
+	tests := map[string]bool{
+		"1.2.3":                  true,
+		"v1.2.3":                 true,
+		"0.0.0":                  true,
+		"1.0.0-alpha":            true,
+		"1.0.0-alpha.1":          true,
+		"1.0.0-rc-1":             true,
+		"1.0.0-0.3.7":            true,
+		"1.0.0+20130313144700":   true,
+		"1.0.0-beta+exp.sha.511": true,
+		"1.5-3":                  false,
+		"2.3-0-0":                false,
+		"1.2":                    false,
+		"1.2.3.4":                false,
+		"1.0.0.a":                false,
+		"01.2.3":                 false,
+		"1.0.0-01":               false,
+		"1:1.2.3":                false,
+	}
+
+	for input, expected := range tests {
+		v, err := New(input)
+		if err != nil {
+			t.Error("expected", input, "to be a valid version but got error", err)
+			continue
+		}
+
+		if v.IsSemVer() != expected {
+			t.Error("expected IsSemVer() of", input, "to be", expected)
+		}
+	}
+}
+
+// WithPrerelease attaches or replaces a prerelease label.
+func Test_WithPrerelease(t *testing.T) {
+	tests := []struct {
+		Version  string
+		Label    string
+		Expected string
+	}{
+		{Version: "1.2.0", Label: "pre.1", Expected: "1.2.0.pre.1"},
+		{Version: "1.2.0.beta.2", Label: "rc1", Expected: "1.2.0.rc.1"},
+		{Version: "1.2.0-3", Label: "alpha", Expected: "1.2.0.alpha"},
+		{Version: "1:1.2.0", Label: "rc.1", Expected: "1:1.2.0.rc.1"},
+	}
+
+	for _, test := range tests {
+		result, err := New2(test.Version).WithPrerelease(test.Label)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if result.Version() != test.Expected {
+			t.Error("expected WithPrerelease(", test.Label, ") of", test.Version, "to be", test.Expected, "but was", result.Version())
+		}
+	}
+
+	for _, label := range []string{"", "1", "pre.", ".pre", "pre..1", "pre+build", "pre_1"} {
+		if result, err := New2("1.2.0").WithPrerelease(label); err == nil {
+			t.Error("expected WithPrerelease(", label, ") to return an error but got", result.Version())
+		}
+	}
+}
+
+// CompareString compares two version strings.
+func Test_CompareString(t *testing.T) {
+	tests := []struct {
+		A        string
+		B        string
+		Expected int
+	}{
+		{A: "1.0", B: "1.1", Expected: -1},
+		{A: "1.0.0", B: "1", Expected: 0},
+		{A: "2.0", B: "2.0.rc1", Expected: 1},
+	}
+
+	for _, test := range tests {
+		result, err := CompareString(test.A, test.B)
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if result != test.Expected {
+			t.Error("expected CompareString(", test.A, ",", test.B, ") to be", test.Expected, "but was", result)
+		}
+	}
+
+	result, err := CompareString("1.0", "1..0")
+	if err == nil || result != 0 {
+		t.Error("expected CompareString to return an error and no result for a malformed version")
+	}
+}
+
+// PrereleaseSegments returns the typed prerelease segments.
+func Test_PrereleaseSegments(t *testing.T) {
+	tests := map[string][]interface{}{
+		"1.0.a.10": {"a", 10},
+		"1.0.a10":  {"a", 10},
+		"1.5-3":    {"pre", 3},
+		"1.2.3":    {},
+	}
+
+	for input, expected := range tests {
+		segments := New2(input).PrereleaseSegments()
+
+		if segments == nil || !reflect.DeepEqual(segments, expected) {
+			t.Error("expected PrereleaseSegments() of", input, "to be", expected, "but was", segments)
+		}
+	}
+}
+
+// BumpLast increments the last numeric release segment.
+func Test_BumpLast(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":     "1.2.4",
+		"1":         "2",
+		"1.9":       "1.10",
+		"1.2.3.b.2": "1.2.4",
+		"1.2-3":     "1.3",
+		"1:1.2.3":   "1:1.2.4",
+	}
+
+	for input, expected := range tests {
+		result, err := New2(input).BumpLast()
+		if err != nil {
+			t.Error("expected no error but received", err)
+			continue
+		}
+
+		if result.Version() != expected {
+			t.Error("expected BumpLast() of", input, "to be", expected, "but was", result.Version())
+		}
+	}
+
+	if _, err := (&Version{version: "a.b"}).BumpLast(); err == nil {
+		t.Error("expected BumpLast() of 'a.b' to return an error")
+	}
+}
+
+// EqlCanonical ignores precision, unlike Eql.
+func Test_EqlCanonical(t *testing.T) {
+	inputs := []string{"1.0", "1", "1.0.0"}
+
+	for _, a := range inputs {
+		for _, b := range inputs {
+			if !New2(a).EqlCanonical(New2(b)) {
+				t.Error("expected", a, "to be EqlCanonical to", b)
+			}
+
+			if New2(a).Eql(New2(b)) != (a == b) {
+				t.Error("expected Eql of", a, "and", b, "to be", a == b)
+			}
+		}
+	}
+
+	if New2("1.0").EqlCanonical(New2("1.0.1")) || New2("1.0").EqlCanonical(New2("1.0.a")) {
+		t.Error("expected different versions not to be EqlCanonical")
+	}
+
+	if !New2("1.a.01").EqlCanonical(New2("1.a.1")) {
+		t.Error("expected 1.a.01 to be EqlCanonical to 1.a.1")
+	}
+}
+
+// A shared *Version can be read from many goroutines at once. Run with
+// -race to check for data races.
+func Test_ConcurrentReads(t *testing.T) {
+	shared := MustNew("1:1.2.3-4+build")
+	other := MustNew("1:1.2.3")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if shared.Compare(other) != -1 || !shared.IsPrerelease() {
+					t.Error("expected", shared.Version(), "to be a prerelease older than", other.Version())
+					return
+				}
+
+				shared.segments()
+				shared.canonicalSegments()
+				shared.Canonical()
+				shared.Release()
+				shared.ApproximateRecommendation()
+				shared.Clone()
+
+				if _, err := shared.Bump(); err != nil {
+					t.Error("expected no error but received", err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}